@@ -0,0 +1,54 @@
+package zerolog_wrapper
+
+import (
+	"strconv"
+
+	"github.com/rs/zerolog"
+)
+
+// WithAllowedFields drops every top level field not named in keys before the
+// event is written, so fields which weren't approved can't leak into the
+// logs, eg: under strict data governance rules. The time, level, message and
+// caller fields are always kept. With countDropped, events which lost fields
+// carry their number as dropped_fields.
+//
+// eg:
+//
+//	log.InitLog(log.InfoLevel, "prod", log.WithAllowedFields(true, "request_id", "status"))
+//	log.Info().Str("request_id", id).Str("email", email).Msg("signup")
+//	// Output: {"level":"info","request_id":"r-1","message":"signup","dropped_fields":1}
+func WithAllowedFields(countDropped bool, keys ...string) Option {
+	return func(o *options) {
+		o.transforms = append(o.transforms, allowedFields(countDropped, keys))
+	}
+}
+
+func allowedFields(countDropped bool, keys []string) fieldTransform {
+	allowed := map[string]bool{
+		zerolog.TimestampFieldName: true,
+		zerolog.LevelFieldName:     true,
+		zerolog.MessageFieldName:   true,
+		zerolog.CallerFieldName:    true,
+	}
+	for _, key := range keys {
+		allowed[key] = true
+	}
+
+	return func(fields []jsonField) []jsonField {
+		kept := fields[:0]
+		dropped := 0
+		for _, field := range fields {
+			if !allowed[field.key] {
+				dropped++
+				continue
+			}
+			kept = append(kept, field)
+		}
+
+		if countDropped && dropped > 0 {
+			kept = append(kept, jsonField{key: "dropped_fields", value: []byte(strconv.Itoa(dropped))})
+		}
+
+		return kept
+	}
+}
@@ -0,0 +1,137 @@
+package zerolog_wrapper
+
+import (
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/rs/zerolog"
+)
+
+// WithAsync hands events to a background goroutine through a queue of size
+// events, so logging never blocks on a slow output. When the queue is full
+// new events are dropped and counted in Stats instead of blocking.
+//
+// Call ShutdownWithTimeout (or Shutdown) before the program exits to write
+// out the queued events.
+func WithAsync(size int) Option {
+	return func(o *options) {
+		o.asyncQueueSize = size
+	}
+}
+
+// DrainStats reports what happened to the queue of the WithAsync writer on shutdown.
+type DrainStats struct {
+	// Drained is the number of queued events written out during shutdown.
+	Drained int
+	// Pending is the number of queued events still not written when the timeout hit.
+	Pending int
+	// Dropped is the number of events dropped because the queue was full since startup.
+	Dropped uint64
+}
+
+type asyncEntry struct {
+	level zerolog.Level
+	p     []byte
+}
+
+// asyncWriter writes events to out from a background goroutine.
+type asyncWriter struct {
+	out      zerolog.LevelWriter
+	mu       sync.RWMutex
+	closed   bool
+	queue    chan asyncEntry
+	inflight sync.WaitGroup
+	done     chan struct{}
+	dropped  atomic.Uint64
+	counters *eventCounters
+}
+
+func newAsyncWriter(out zerolog.LevelWriter, size int, counters *eventCounters) *asyncWriter {
+	w := &asyncWriter{
+		out:      out,
+		queue:    make(chan asyncEntry, size),
+		done:     make(chan struct{}),
+		counters: counters,
+	}
+	go w.run()
+
+	return w
+}
+
+func (w *asyncWriter) run() {
+	defer close(w.done)
+
+	for entry := range w.queue {
+		_, _ = w.out.WriteLevel(entry.level, entry.p)
+		w.inflight.Done()
+	}
+}
+
+func (w *asyncWriter) Write(p []byte) (int, error) {
+	return w.WriteLevel(zerolog.NoLevel, p)
+}
+
+func (w *asyncWriter) WriteLevel(level zerolog.Level, p []byte) (int, error) {
+	w.mu.RLock()
+	defer w.mu.RUnlock()
+
+	if w.closed {
+		// events logged after shutdown are written right away
+		return w.out.WriteLevel(level, p)
+	}
+
+	// zerolog reuses the event buffer once the write returns
+	entry := asyncEntry{level: level, p: append([]byte(nil), p...)}
+	w.inflight.Add(1)
+	select {
+	case w.queue <- entry:
+	default:
+		w.inflight.Done()
+		w.dropped.Add(1)
+		w.counters.countDropped(level)
+	}
+
+	return len(p), nil
+}
+
+// Flush waits until every queued event is written.
+func (w *asyncWriter) Flush() error {
+	w.inflight.Wait()
+
+	return nil
+}
+
+// close stops accepting new events into the queue and waits up to timeout,
+// or without limit for a zero timeout, for the queued events to be written.
+func (w *asyncWriter) close(timeout time.Duration) DrainStats {
+	w.mu.Lock()
+	if w.closed {
+		w.mu.Unlock()
+		return DrainStats{Dropped: w.dropped.Load()}
+	}
+	w.closed = true
+	queued := len(w.queue)
+	close(w.queue)
+	w.mu.Unlock()
+
+	var expired <-chan time.Time
+	if timeout > 0 {
+		timer := time.NewTimer(timeout)
+		defer timer.Stop()
+		expired = timer.C
+	}
+
+	pending := 0
+	select {
+	case <-w.done:
+	case <-expired:
+		pending = len(w.queue)
+	}
+
+	return DrainStats{
+		Drained: queued - pending,
+		Pending: pending,
+		Dropped: w.dropped.Load(),
+	}
+}
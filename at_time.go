@@ -0,0 +1,65 @@
+package zerolog_wrapper
+
+import (
+	"bytes"
+	"sync/atomic"
+	"time"
+
+	"github.com/rs/zerolog"
+)
+
+// timeOverrides is set once AtTime was used, before that events skip the check for a second timestamp.
+var timeOverrides atomic.Bool
+
+// AtTime sets the timestamp of a single event to t instead of now, eg: when
+// replaying or backfilling historical data with the original times.
+//
+// Use it with the Func method of an event:
+//
+//	log.Info().Func(log.AtTime(record.CreatedAt)).Str("order_id", record.ID).Msg("order imported")
+func AtTime(t time.Time) func(e *zerolog.Event) {
+	return func(e *zerolog.Event) {
+		timeOverrides.Store(true)
+		e.Time(zerolog.TimestampFieldName, t)
+	}
+}
+
+// timeOverrideWriter drops the timestamp added by the logger from events whose time was set by AtTime.
+// The logger adds its timestamp last, so the first one is kept.
+type timeOverrideWriter struct {
+	out zerolog.LevelWriter
+}
+
+func (w timeOverrideWriter) Write(p []byte) (int, error) {
+	return w.WriteLevel(zerolog.NoLevel, p)
+}
+
+func (w timeOverrideWriter) WriteLevel(level zerolog.Level, p []byte) (int, error) {
+	if !timeOverrides.Load() || bytes.Count(p, []byte(`"`+zerolog.TimestampFieldName+`":`)) < 2 {
+		return w.out.WriteLevel(level, p)
+	}
+
+	fields, err := decodeFields(p)
+	if err != nil {
+		return w.out.WriteLevel(level, p)
+	}
+
+	kept := fields[:0]
+	seen := false
+	for _, field := range fields {
+		if field.key == zerolog.TimestampFieldName {
+			if seen {
+				continue
+			}
+			seen = true
+		}
+		kept = append(kept, field)
+	}
+
+	if _, err := w.out.WriteLevel(level, encodeFields(kept)); err != nil {
+		return 0, err
+	}
+
+	// report the original length, zerolog treats anything else as a short write
+	return len(p), nil
+}
@@ -0,0 +1,68 @@
+package zerolog_wrapper
+
+import (
+	"bytes"
+	"io"
+	"sync"
+	"testing"
+)
+
+func TestBenchmarkInstanceIsIsolated(t *testing.T) {
+	var sampledOut int
+	l, err := New(InfoLevel, Prod, DisableHostIP(), WithOutput(&bytes.Buffer{}),
+		WithSampling(SamplerConfig{InfoRate: 10}),
+		WithOnSampledOut(func(level LogLevel, msg string) { sampledOut++ }))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	b := l.benchmarkInstance()
+	for i := 0; i < 1000; i++ {
+		b.Info().Msg("benchmark event")
+	}
+
+	if got := l.Stats()[InfoLevel].SampledOut; got != 0 {
+		t.Errorf("benchmark events counted in Stats: %d sampled out", got)
+	}
+	if sampledOut != 0 {
+		t.Errorf("WithOnSampledOut called %d times by the benchmark", sampledOut)
+	}
+	if got := b.Stats()[InfoLevel].SampledOut; got != 900 {
+		t.Errorf("benchmark logger sampled out %d events, want 900", got)
+	}
+}
+
+func TestBenchmarkWhileLogging(t *testing.T) {
+	l, err := New(TraceLevel, Prod, DisableHostIP(), WithOutput(io.Discard))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	done, logging := make(chan struct{}), make(chan struct{})
+	var wg sync.WaitGroup
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		close(logging)
+		for {
+			select {
+			case <-done:
+				return
+			default:
+				l.Info().Msg("serving")
+			}
+		}
+	}()
+
+	<-logging
+	for n := 0; n < 100; n++ {
+		b := l.benchmarkInstance()
+		b.Info().Msg("benchmark event")
+	}
+	if _, err := New(InfoLevel, Prod, WithOutput(io.Discard)); err != nil {
+		t.Fatal(err)
+	}
+
+	close(done)
+	wg.Wait()
+}
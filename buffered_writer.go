@@ -0,0 +1,170 @@
+package zerolog_wrapper
+
+import (
+	"errors"
+	"io"
+	"sync"
+	"time"
+
+	"github.com/rs/zerolog"
+)
+
+// WithBuffer collects the formatted events in memory right above the output
+// and writes them out in batches, one write for many events, once size bytes
+// are buffered or every flushInterval, whichever comes first. Error and higher
+// events are written out right away together with everything buffered before
+// them. Outputs implementing zerolog.LevelWriter are still written one event
+// at a time, so they receive the level of each.
+//
+// Call Shutdown (or Flush) before the program exits so buffered events aren't lost.
+func WithBuffer(size int, flushInterval time.Duration) Option {
+	return func(o *options) {
+		o.bufferSize = size
+		o.flushInterval = flushInterval
+	}
+}
+
+// bufferedWriter batches the formatted events right above a destination, so
+// they are written out with one write per batch instead of one per event.
+// Destinations implementing zerolog.LevelWriter get one write per event
+// instead, each with its level.
+type bufferedWriter struct {
+	mu  sync.Mutex
+	out io.Writer
+	buf []byte
+	// entries holds the level and the end offset in buf of each buffered
+	// event, only when out is a zerolog.LevelWriter
+	entries []bufferedEntry
+	levels  bool
+	size    int
+}
+
+type bufferedEntry struct {
+	level zerolog.Level
+	end   int
+}
+
+func newBufferedWriter(out io.Writer, size int) *bufferedWriter {
+	_, levels := out.(zerolog.LevelWriter)
+
+	return &bufferedWriter{
+		out:    out,
+		buf:    make([]byte, 0, size),
+		levels: levels,
+		size:   size,
+	}
+}
+
+func (w *bufferedWriter) Write(p []byte) (int, error) {
+	return w.WriteLevel(zerolog.NoLevel, p)
+}
+
+func (w *bufferedWriter) WriteLevel(level zerolog.Level, p []byte) (int, error) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	if len(w.buf) > 0 && len(w.buf)+len(p) > w.size {
+		if err := w.flush(); err != nil {
+			return 0, err
+		}
+	}
+	w.buf = append(w.buf, p...)
+	if w.levels {
+		w.entries = append(w.entries, bufferedEntry{level: level, end: len(w.buf)})
+	}
+
+	if len(w.buf) >= w.size {
+		// the event is kept for the next flush when this one fails
+		return len(p), w.flush()
+	}
+
+	return len(p), nil
+}
+
+// writeOut writes out the buffered events. It isn't called Flush, as the
+// writers in front of it flush the writer behind them after every event.
+func (w *bufferedWriter) writeOut() error {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	return w.flush()
+}
+
+// Close writes out the buffered events, Shutdown closes the writers after flushing
+// them, so this catches what the writers in front of it write when closed, eg: the gzip trailer.
+func (w *bufferedWriter) Close() error {
+	return w.writeOut()
+}
+
+// flush writes out the buffered events. What couldn't be written stays
+// buffered for the next flush.
+func (w *bufferedWriter) flush() error {
+	if len(w.buf) == 0 {
+		return nil
+	}
+
+	if w.levels {
+		start := 0
+		for n, entry := range w.entries {
+			if _, err := w.out.(zerolog.LevelWriter).WriteLevel(entry.level, w.buf[start:entry.end]); err != nil {
+				w.consume(start, n)
+				return err
+			}
+			start = entry.end
+		}
+	} else {
+		n, err := w.out.Write(w.buf)
+		if err == nil && n < len(w.buf) {
+			err = io.ErrShortWrite
+		}
+		if err != nil {
+			w.consume(n, 0)
+			return err
+		}
+	}
+
+	w.buf = w.buf[:0]
+	w.entries = w.entries[:0]
+
+	return nil
+}
+
+// consume drops the first n bytes and the first entries entries, which were written out.
+func (w *bufferedWriter) consume(n, entries int) {
+	w.buf = w.buf[:copy(w.buf, w.buf[n:])]
+	w.entries = w.entries[:copy(w.entries, w.entries[entries:])]
+	for i := range w.entries {
+		w.entries[i].end -= n
+	}
+}
+
+// bufferFlushWriter writes out buffers right after an error or higher event
+// was written to out, together with everything buffered before it.
+type bufferFlushWriter struct {
+	out     zerolog.LevelWriter
+	buffers []*bufferedWriter
+}
+
+func (w bufferFlushWriter) Write(p []byte) (int, error) {
+	return w.WriteLevel(zerolog.NoLevel, p)
+}
+
+func (w bufferFlushWriter) WriteLevel(level zerolog.Level, p []byte) (int, error) {
+	n, err := w.out.WriteLevel(level, p)
+	if level >= zerolog.ErrorLevel && level <= zerolog.PanicLevel {
+		for _, b := range w.buffers {
+			err = errors.Join(err, b.writeOut())
+		}
+	}
+
+	return n, err
+}
+
+// flushBuffers writes out buffers.
+func flushBuffers(buffers []*bufferedWriter) func() {
+	return func() {
+		for _, b := range buffers {
+			_ = b.writeOut()
+		}
+	}
+}
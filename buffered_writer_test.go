@@ -0,0 +1,153 @@
+package zerolog_wrapper
+
+import (
+	"bytes"
+	"errors"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestBufferedWriterReplaysEventsWithLevel(t *testing.T) {
+	var all, errs bytes.Buffer
+	l, err := New(DebugLevel, Prod, DisableHostIP(), WithBuffer(4096, time.Hour), WithOutputs([]OutputSpec{
+		{Writer: &all, MinLevel: DebugLevel},
+		{Writer: &errs, MinLevel: ErrorLevel},
+	}))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	l.Info().Msg("one")
+	l.Info().Msg("two")
+	l.Info().Msg("three")
+	if err := l.Flush(); err != nil {
+		t.Fatal(err)
+	}
+
+	if got := strings.Count(all.String(), "\n"); got != 3 {
+		t.Errorf("got %d events, want 3:\n%s", got, all.String())
+	}
+	if errs.Len() != 0 {
+		t.Errorf("info events reached the error output:\n%s", errs.String())
+	}
+}
+
+// writeCounter counts the writes to w.
+type writeCounter struct {
+	w      io.Writer
+	writes int
+}
+
+func (c *writeCounter) Write(p []byte) (int, error) {
+	c.writes++
+	return c.w.Write(p)
+}
+
+// failingWriter fails every write while failing is set, writing half of p first.
+type failingWriter struct {
+	bytes.Buffer
+	failing bool
+}
+
+func (w *failingWriter) Write(p []byte) (int, error) {
+	if w.failing {
+		n, _ := w.Buffer.Write(p[:len(p)/2])
+		return n, errors.New("write failed")
+	}
+	return w.Buffer.Write(p)
+}
+
+func TestBufferedWriterBatchesWrites(t *testing.T) {
+	var buf bytes.Buffer
+	out := &writeCounter{w: &buf}
+	l, err := New(InfoLevel, Prod, DisableHostIP(), WithOutput(out), WithBuffer(4096, time.Hour))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for n := 0; n < 10; n++ {
+		l.Info().Int("n", n).Msg("batched")
+	}
+	if out.writes != 0 {
+		t.Fatalf("%d writes before the buffer was flushed", out.writes)
+	}
+
+	l.Error().Msg("written out right away")
+	if out.writes != 1 {
+		t.Errorf("got %d writes, want the whole batch in one", out.writes)
+	}
+	if got := strings.Count(buf.String(), "\n"); got != 11 {
+		t.Errorf("got %d events, want 11:\n%s", got, buf.String())
+	}
+}
+
+func TestBufferedWriterKeepsUnwrittenEvents(t *testing.T) {
+	out := &failingWriter{failing: true}
+	w := newBufferedWriter(out, 4096)
+
+	for _, event := range []string{"one\n", "two\n", "three\n"} {
+		if _, err := w.Write([]byte(event)); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := w.writeOut(); err == nil {
+		t.Fatal("writeOut didn't report the write error")
+	}
+
+	out.failing = false
+	if err := w.writeOut(); err != nil {
+		t.Fatal(err)
+	}
+	if out.String() != "one\ntwo\nthree\n" {
+		t.Errorf("got %q, want every event exactly once", out.String())
+	}
+}
+
+// benchmarkFile logs b.N events to a file through a logger set up with opts,
+// reporting the writes to the file per event.
+func benchmarkFile(b *testing.B, opts ...Option) {
+	f, err := os.Create(filepath.Join(b.TempDir(), "bench.log"))
+	if err != nil {
+		b.Fatal(err)
+	}
+	defer f.Close()
+
+	out := &writeCounter{w: f}
+	l, err := New(InfoLevel, Prod, append(opts, DisableHostIP(), WithOutput(out))...)
+	if err != nil {
+		b.Fatal(err)
+	}
+
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		l.Info().Str("key", "value").Int("n", i).Msg("benchmark")
+	}
+	b.StopTimer()
+	_ = l.Shutdown()
+	b.ReportMetric(float64(out.writes)/float64(b.N), "writes/op")
+}
+
+func BenchmarkUnbufferedFile(b *testing.B) {
+	benchmarkFile(b)
+}
+
+func BenchmarkBufferedFile(b *testing.B) {
+	benchmarkFile(b, WithBuffer(64<<10, time.Second))
+}
+
+func BenchmarkBufferedDiscard(b *testing.B) {
+	l, err := New(InfoLevel, Prod, DisableHostIP(), WithOutput(io.Discard), WithBuffer(64<<10, time.Second))
+	if err != nil {
+		b.Fatal(err)
+	}
+
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		l.Info().Str("key", "value").Msg("benchmark")
+	}
+}
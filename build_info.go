@@ -0,0 +1,38 @@
+package zerolog_wrapper
+
+import "sync/atomic"
+
+// buildVersion can be set at link time, see SetBuildInfo.
+var buildVersion string
+
+// buildInfo holds the version set through SetBuildInfo.
+var buildInfo atomic.Value
+
+// SetBuildInfo sets the source revision the binary was built from. It is
+// appended to the caller field, eg: "handler.go:42@3f2a1bc", so a log line
+// can be traced back to the exact source it came from.
+//
+// The revision is usually injected at build time into a variable of the
+// application and passed on here:
+//
+//	var revision string // go build -ldflags "-X main.revision=$(git rev-parse --short HEAD)"
+//
+//	func init() {
+//		log.SetBuildInfo(revision)
+//		log.InitLog(log.TraceLevel, "prod")
+//	}
+//
+// Alternatively it can be injected directly with
+// -ldflags "-X github.com/ashokrajar/zerolog_wrapper.buildVersion=$(git rev-parse --short HEAD)".
+func SetBuildInfo(version string) {
+	buildInfo.Store(version)
+}
+
+// currentBuildVersion returns the version set through SetBuildInfo or at link time.
+func currentBuildVersion() string {
+	if version, ok := buildInfo.Load().(string); ok {
+		return version
+	}
+
+	return buildVersion
+}
@@ -0,0 +1,65 @@
+package zerolog_wrapper
+
+import (
+	"os"
+	"path/filepath"
+	"runtime"
+	"strings"
+	"sync"
+)
+
+var (
+	callerPrefixesOnce sync.Once
+	callerPrefixes     []string
+)
+
+// sourcePrefixes returns the directories trimmed from caller file names,
+// looked up once: the working directory, the source directories of GOROOT
+// and of each GOPATH entry.
+func sourcePrefixes() []string {
+	callerPrefixesOnce.Do(func() {
+		if dir, err := os.Getwd(); err == nil {
+			callerPrefixes = append(callerPrefixes, filepath.ToSlash(dir)+"/")
+		}
+		if root := runtime.GOROOT(); root != "" {
+			callerPrefixes = append(callerPrefixes, filepath.ToSlash(root)+"/src/")
+		}
+
+		gopath := os.Getenv("GOPATH")
+		if gopath == "" {
+			if home, err := os.UserHomeDir(); err == nil {
+				gopath = filepath.Join(home, "go")
+			}
+		}
+		for _, dir := range filepath.SplitList(gopath) {
+			if dir != "" {
+				callerPrefixes = append(callerPrefixes, filepath.ToSlash(dir)+"/src/")
+			}
+		}
+	})
+
+	return callerPrefixes
+}
+
+// trimCallerPath shortens the file name of a caller to a path relative to
+// where it lives: files in the module cache become
+// module@version/file.go, vendored files the import path of their package,
+// files of the standard library and GOPATH mode builds the path below their
+// src directory and everything else the path relative to the working directory.
+func trimCallerPath(file string) string {
+	// vendored and module cache files are recognized by their path alone,
+	// wherever the vendor directory or the module cache is
+	for _, marker := range []string{"/vendor/", "/pkg/mod/"} {
+		if i := strings.LastIndex(file, marker); i >= 0 {
+			return file[i+len(marker):]
+		}
+	}
+
+	for _, prefix := range sourcePrefixes() {
+		if strings.HasPrefix(file, prefix) {
+			return file[len(prefix):]
+		}
+	}
+
+	return file
+}
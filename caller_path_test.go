@@ -0,0 +1,38 @@
+package zerolog_wrapper
+
+import (
+	"os"
+	"path/filepath"
+	"runtime"
+	"testing"
+)
+
+func TestTrimCallerPath(t *testing.T) {
+	wd, err := os.Getwd()
+	if err != nil {
+		t.Fatal(err)
+	}
+	goroot := filepath.ToSlash(runtime.GOROOT())
+
+	tests := []struct {
+		name string
+		file string
+		want string
+	}{
+		{"module cache", "/home/dev/go/pkg/mod/github.com/rs/zerolog@v1.29.1/log.go", "github.com/rs/zerolog@v1.29.1/log.go"},
+		{"module cache elsewhere", "/cache/gomod/pkg/mod/golang.org/x/net@v0.10.0/http2/server.go", "golang.org/x/net@v0.10.0/http2/server.go"},
+		{"vendor", "/build/app/vendor/github.com/acme/lib/client.go", "github.com/acme/lib/client.go"},
+		{"nested vendor", "/build/app/vendor/github.com/acme/lib/vendor/github.com/dep/x.go", "github.com/dep/x.go"},
+		{"GOROOT", goroot + "/src/net/http/server.go", "net/http/server.go"},
+		{"working directory", filepath.ToSlash(wd) + "/cmd/app/main.go", "cmd/app/main.go"},
+		{"unknown", "/somewhere/else/main.go", "/somewhere/else/main.go"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := trimCallerPath(tt.file); got != tt.want {
+				t.Errorf("trimCallerPath(%q) = %q, want %q", tt.file, got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,203 @@
+package zerolog_wrapper
+
+import (
+	"errors"
+	"io"
+	"sync"
+	"time"
+
+	"github.com/rs/zerolog"
+)
+
+// BreakerState is the state of a CircuitBreakerWriter.
+type BreakerState string
+
+const (
+	// BreakerClosed writes to the primary writer.
+	BreakerClosed BreakerState = "closed"
+	// BreakerOpen writes to the fallback writer only until the cooldown is over.
+	BreakerOpen BreakerState = "open"
+	// BreakerHalfOpen probes the primary writer with the next event.
+	BreakerHalfOpen BreakerState = "half_open"
+)
+
+var (
+	// errBreakerOpen is returned for events written while the breaker is open without a fallback writer.
+	errBreakerOpen = errors.New("circuit breaker open")
+	// errWriteTimeout is the failure of a write to the primary writer which outlasted the write timeout.
+	errWriteTimeout = errors.New("circuit breaker write timeout")
+)
+
+// BreakerStats reports the state of a CircuitBreakerWriter.
+type BreakerStats struct {
+	State BreakerState
+	// ConsecutiveFailures counts the failed writes to the primary writer since the last successful one.
+	ConsecutiveFailures int
+	// Trips counts how often the breaker opened.
+	Trips uint64
+	// FallbackWrites counts the events written to the fallback writer.
+	FallbackWrites uint64
+	// OpenedAt is when the breaker last opened.
+	OpenedAt time.Time
+}
+
+// CircuitBreakerWriter guards a primary writer which can fail, eg: one
+// shipping events over the network. After threshold consecutive failed
+// writes the breaker opens and events go to the fallback writer only, so a
+// dead destination doesn't add its timeout to every log call. Once cooldown
+// passed, the next event probes the primary writer, closing the breaker when
+// the write works and opening it again otherwise.
+//
+// A primary writer which stalls instead of failing blocks logging, unless
+// WithBreakerWriteTimeout turns writes outlasting a timeout into failures.
+//
+// The state of the breaker isn't part of Stats, which counts the events of
+// each level of a logger which may have several outputs. Report Stats of the
+// writer itself instead.
+//
+// Use it with WithOutput:
+//
+//	w := log.NewCircuitBreakerWriter(httpWriter, os.Stderr, 5, 30*time.Second,
+//		log.WithBreakerWriteTimeout(time.Second))
+//	log.InitLog(log.InfoLevel, "prod", log.WithOutput(w))
+type CircuitBreakerWriter struct {
+	mu           sync.Mutex
+	primary      io.Writer
+	fallback     io.Writer
+	threshold    int
+	cooldown     time.Duration
+	writeTimeout time.Duration
+	stats        BreakerStats
+
+	// primaryMu serializes the writes to primary, which mu isn't held across
+	primaryMu sync.Mutex
+}
+
+// BreakerOption configures a CircuitBreakerWriter.
+type BreakerOption func(*CircuitBreakerWriter)
+
+// WithBreakerWriteTimeout fails writes to the primary writer which take
+// longer than timeout, so a stalled primary writer trips the breaker like a
+// failing one. The event then goes to the fallback writer, while the stalled
+// write carries on in the background and may still deliver it to the primary
+// writer as well. A zero or negative timeout waits for every write, the default.
+func WithBreakerWriteTimeout(timeout time.Duration) BreakerOption {
+	return func(w *CircuitBreakerWriter) {
+		w.writeTimeout = timeout
+	}
+}
+
+// NewCircuitBreakerWriter returns a CircuitBreakerWriter over primary. A
+// nil fallback drops the events while the breaker is open, a threshold below 1 opens on the first failure.
+func NewCircuitBreakerWriter(primary, fallback io.Writer, threshold int, cooldown time.Duration, opts ...BreakerOption) *CircuitBreakerWriter {
+	if threshold < 1 {
+		threshold = 1
+	}
+
+	w := &CircuitBreakerWriter{
+		primary:   primary,
+		fallback:  fallback,
+		threshold: threshold,
+		cooldown:  cooldown,
+		stats:     BreakerStats{State: BreakerClosed},
+	}
+	for _, opt := range opts {
+		opt(w)
+	}
+
+	return w
+}
+
+// Stats returns the current state of the breaker.
+func (w *CircuitBreakerWriter) Stats() BreakerStats {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	stats := w.stats
+	if stats.State == BreakerOpen && time.Since(stats.OpenedAt) >= w.cooldown {
+		stats.State = BreakerHalfOpen
+	}
+
+	return stats
+}
+
+func (w *CircuitBreakerWriter) Write(p []byte) (int, error) {
+	return w.WriteLevel(zerolog.NoLevel, p)
+}
+
+func (w *CircuitBreakerWriter) WriteLevel(level zerolog.Level, p []byte) (int, error) {
+	w.mu.Lock()
+	if w.stats.State == BreakerOpen && time.Since(w.stats.OpenedAt) < w.cooldown {
+		defer w.mu.Unlock()
+		return w.writeFallback(level, p, errBreakerOpen)
+	}
+	w.mu.Unlock()
+
+	n, err := w.writePrimary(level, p)
+
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	if err == nil {
+		w.stats.State = BreakerClosed
+		w.stats.ConsecutiveFailures = 0
+		return n, nil
+	}
+
+	w.stats.ConsecutiveFailures++
+	// a failed probe opens the breaker again right away
+	if w.stats.State == BreakerOpen || w.stats.ConsecutiveFailures >= w.threshold {
+		w.stats.State = BreakerOpen
+		w.stats.OpenedAt = time.Now()
+		w.stats.Trips++
+	}
+
+	return w.writeFallback(level, p, err)
+}
+
+// writePrimary writes p to the primary writer, failing with errWriteTimeout
+// when that takes longer than the write timeout.
+func (w *CircuitBreakerWriter) writePrimary(level zerolog.Level, p []byte) (int, error) {
+	if w.writeTimeout <= 0 {
+		w.primaryMu.Lock()
+		defer w.primaryMu.Unlock()
+
+		return writeLevel(w.primary, level, p)
+	}
+
+	type result struct {
+		n   int
+		err error
+	}
+	done := make(chan result, 1)
+	// zerolog reuses p once the write returns, while the write may carry on
+	event := append([]byte(nil), p...)
+	go func() {
+		w.primaryMu.Lock()
+		defer w.primaryMu.Unlock()
+
+		n, err := writeLevel(w.primary, level, event)
+		done <- result{n, err}
+	}()
+
+	timer := time.NewTimer(w.writeTimeout)
+	defer timer.Stop()
+
+	select {
+	case r := <-done:
+		return r.n, r.err
+	case <-timer.C:
+		return 0, errWriteTimeout
+	}
+}
+
+// writeFallback writes p to the fallback writer, failing with cause without one.
+func (w *CircuitBreakerWriter) writeFallback(level zerolog.Level, p []byte, cause error) (int, error) {
+	if w.fallback == nil {
+		return 0, cause
+	}
+
+	w.stats.FallbackWrites++
+
+	return writeLevel(w.fallback, level, p)
+}
@@ -0,0 +1,92 @@
+package zerolog_wrapper
+
+import (
+	"bytes"
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestCircuitBreakerWriter(t *testing.T) {
+	primary := &failingWriter{failing: true}
+	var fallback bytes.Buffer
+	w := NewCircuitBreakerWriter(primary, &fallback, 2, time.Hour)
+
+	for i := 0; i < 3; i++ {
+		if _, err := w.Write([]byte("event\n")); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	stats := w.Stats()
+	if stats.State != BreakerOpen || stats.Trips != 1 || stats.FallbackWrites != 3 || stats.ConsecutiveFailures != 2 {
+		t.Errorf("unexpected stats after 3 failed writes: %+v", stats)
+	}
+	if got := bytes.Count(fallback.Bytes(), []byte("event\n")); got != 3 {
+		t.Errorf("fallback got %d events, want 3", got)
+	}
+}
+
+func TestCircuitBreakerWriterProbe(t *testing.T) {
+	primary := &failingWriter{failing: true}
+	w := NewCircuitBreakerWriter(primary, nil, 1, 10*time.Millisecond)
+
+	if _, err := w.Write([]byte("lost\n")); err == nil {
+		t.Fatal("failed write without fallback returned no error")
+	}
+	if _, err := w.Write([]byte("dropped\n")); !errors.Is(err, errBreakerOpen) {
+		t.Fatalf("write while open = %v, want %v", err, errBreakerOpen)
+	}
+
+	time.Sleep(20 * time.Millisecond)
+	if state := w.Stats().State; state != BreakerHalfOpen {
+		t.Fatalf("state after cooldown = %s, want %s", state, BreakerHalfOpen)
+	}
+
+	primary.failing = false
+	if _, err := w.Write([]byte("probe\n")); err != nil {
+		t.Fatal(err)
+	}
+	if state := w.Stats().State; state != BreakerClosed {
+		t.Errorf("state after a working probe = %s, want %s", state, BreakerClosed)
+	}
+	if !bytes.Contains(primary.Bytes(), []byte("probe\n")) {
+		t.Errorf("probe not written to the primary writer: %q", primary.String())
+	}
+}
+
+// stallingWriter blocks every write until release is closed.
+type stallingWriter struct {
+	release chan struct{}
+}
+
+func (w stallingWriter) Write(p []byte) (int, error) {
+	<-w.release
+	return len(p), nil
+}
+
+func TestCircuitBreakerWriterStall(t *testing.T) {
+	primary := stallingWriter{release: make(chan struct{})}
+	defer close(primary.release)
+	var fallback bytes.Buffer
+	w := NewCircuitBreakerWriter(primary, &fallback, 2, time.Hour, WithBreakerWriteTimeout(10*time.Millisecond))
+
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		for i := 0; i < 3; i++ {
+			_, _ = w.Write([]byte("event\n"))
+		}
+	}()
+
+	select {
+	case <-done:
+	case <-time.After(5 * time.Second):
+		t.Fatal("a stalled primary writer blocked logging")
+	}
+
+	stats := w.Stats()
+	if stats.State != BreakerOpen || stats.FallbackWrites != 3 {
+		t.Errorf("unexpected stats after a stall: %+v", stats)
+	}
+}
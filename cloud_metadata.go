@@ -0,0 +1,159 @@
+package zerolog_wrapper
+
+import (
+	"context"
+	"errors"
+	"io"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/rs/zerolog"
+)
+
+type CloudProvider string
+
+const (
+	CloudNone CloudProvider = "none"
+	CloudAWS  CloudProvider = "aws"
+	CloudGCP  CloudProvider = "gcp"
+)
+
+// metadataTimeout bounds the whole metadata lookup.
+var metadataTimeout = 2 * time.Second
+
+var (
+	awsMetadataURL = "http://169.254.169.254/latest"
+	gcpMetadataURL = "http://metadata.google.internal/computeMetadata/v1"
+)
+
+// cloudMetadata holds the instance details looked up from a metadata service.
+type cloudMetadata struct {
+	instanceID, region, zone string
+}
+
+// WithCloudMetadata adds the instance ID, region and availability zone of the
+// instance as cloud_instance_id, cloud_region and cloud_availability_zone to
+// every event, looked up from the metadata service of provider.
+//
+// The lookup runs in the background with a short timeout so it never delays
+// startup, events logged before it finishes lack the fields. When the metadata
+// service is unreachable the fields are left out.
+func WithCloudMetadata(provider CloudProvider) Option {
+	return func(o *options) {
+		o.cloudProvider = provider
+	}
+}
+
+// addCloudMetadata looks up the metadata of provider and adds it to the instance's context.
+func (i *Instance) addCloudMetadata(ctx context.Context, provider CloudProvider) {
+	ctx, cancel := context.WithTimeout(ctx, metadataTimeout)
+	defer cancel()
+
+	var md cloudMetadata
+	var err error
+	switch provider {
+	case CloudAWS:
+		md, err = fetchAWSMetadata(ctx)
+	case CloudGCP:
+		md, err = fetchGCPMetadata(ctx)
+	default:
+		return
+	}
+	if err != nil {
+		i.Debug().Err(err).Str("cloud_provider", string(provider)).Msg("cloud metadata unavailable")
+		return
+	}
+
+	i.updateContext(func(c zerolog.Context) zerolog.Context {
+		return c.
+			Str("cloud_provider", string(provider)).
+			Str("cloud_instance_id", md.instanceID).
+			Str("cloud_region", md.region).
+			Str("cloud_availability_zone", md.zone)
+	})
+}
+
+// fetchAWSMetadata queries the EC2 instance metadata service (IMDSv2).
+func fetchAWSMetadata(ctx context.Context) (cloudMetadata, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodPut, awsMetadataURL+"/api/token", nil)
+	if err != nil {
+		return cloudMetadata{}, err
+	}
+	req.Header.Set("X-aws-ec2-metadata-token-ttl-seconds", "60")
+	token, err := fetchMetadata(req)
+	if err != nil {
+		return cloudMetadata{}, err
+	}
+
+	get := func(path string) (string, error) {
+		req, err := http.NewRequestWithContext(ctx, http.MethodGet, awsMetadataURL+"/meta-data/"+path, nil)
+		if err != nil {
+			return "", err
+		}
+		req.Header.Set("X-aws-ec2-metadata-token", token)
+		return fetchMetadata(req)
+	}
+
+	var md cloudMetadata
+	if md.instanceID, err = get("instance-id"); err != nil {
+		return cloudMetadata{}, err
+	}
+	if md.region, err = get("placement/region"); err != nil {
+		return cloudMetadata{}, err
+	}
+	if md.zone, err = get("placement/availability-zone"); err != nil {
+		return cloudMetadata{}, err
+	}
+
+	return md, nil
+}
+
+// fetchGCPMetadata queries the GCE metadata server.
+func fetchGCPMetadata(ctx context.Context) (cloudMetadata, error) {
+	get := func(path string) (string, error) {
+		req, err := http.NewRequestWithContext(ctx, http.MethodGet, gcpMetadataURL+"/instance/"+path, nil)
+		if err != nil {
+			return "", err
+		}
+		req.Header.Set("Metadata-Flavor", "Google")
+		return fetchMetadata(req)
+	}
+
+	var md cloudMetadata
+	var err error
+	if md.instanceID, err = get("id"); err != nil {
+		return cloudMetadata{}, err
+	}
+	zone, err := get("zone")
+	if err != nil {
+		return cloudMetadata{}, err
+	}
+
+	// the zone comes as projects/<number>/zones/<zone>, the region is the zone without its suffix
+	md.zone = zone[strings.LastIndex(zone, "/")+1:]
+	if i := strings.LastIndex(md.zone, "-"); i > 0 {
+		md.region = md.zone[:i]
+	}
+
+	return md, nil
+}
+
+// fetchMetadata performs req and returns the trimmed response body.
+func fetchMetadata(req *http.Request) (string, error) {
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return "", err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return "", errors.New("metadata service returned " + resp.Status)
+	}
+	body, err := io.ReadAll(io.LimitReader(resp.Body, 4096))
+	if err != nil {
+		return "", err
+	}
+
+	return strings.TrimSpace(string(body)), nil
+}
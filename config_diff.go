@@ -0,0 +1,83 @@
+package zerolog_wrapper
+
+import (
+	"encoding/json"
+	"reflect"
+
+	"github.com/rs/zerolog"
+)
+
+// configChange is a single changed field found by ConfigDiff.
+type configChange struct {
+	path     string
+	old, new json.RawMessage
+}
+
+// ConfigDiff compares the structs old and new, eg: a configuration before and
+// after a reload, and logs the changed fields at info level under changes, each
+// with its old and new value. Nested structs are compared field by field and
+// named by their dotted path. Field names follow the json tags and fields
+// tagged log:"redact" are logged as "[REDACTED]", also inside changed values
+// such as a struct which was nil before or a slice of structs. Nothing is
+// logged when nothing changed.
+//
+// eg:
+//
+//	log.ConfigDiff(oldCfg, newCfg)
+//	// Output: {"level":"info","changes":{"db.host":{"old":"db1","new":"db2"},"db.password":{"old":"[REDACTED]","new":"[REDACTED]"}},"message":"config changed"}
+func ConfigDiff(old, new interface{}) {
+	changes := diffValues("", reflect.ValueOf(old), reflect.ValueOf(new), false, nil)
+	if len(changes) == 0 {
+		return
+	}
+
+	dict := zerolog.Dict()
+	for _, change := range changes {
+		dict.Dict(change.path, zerolog.Dict().
+			RawJSON("old", change.old).
+			RawJSON("new", change.new))
+	}
+	Info().Dict("changes", dict).Msg("config changed")
+}
+
+// diffValues appends the differences between old and new to changes.
+func diffValues(path string, old, new reflect.Value, redact bool, changes []configChange) []configChange {
+	for old.Kind() == reflect.Pointer && !old.IsNil() {
+		old = old.Elem()
+	}
+	for new.Kind() == reflect.Pointer && !new.IsNil() {
+		new = new.Elem()
+	}
+
+	if old.IsValid() && new.IsValid() && old.Type() == new.Type() && old.Kind() == reflect.Struct && !redact {
+		for _, field := range structFields(old.Type()) {
+			// a field held by a nil embedded pointer is missing, like a nil pointer
+			oldField, _, _ := fieldByIndex(old, field.index, nil)
+			newField, _, _ := fieldByIndex(new, field.index, nil)
+			name := field.name
+			if path != "" {
+				name = path + "." + name
+			}
+			changes = diffValues(name, oldField, newField, field.redact, changes)
+		}
+		return changes
+	}
+
+	if reflect.DeepEqual(interfaceOf(old), interfaceOf(new)) {
+		return changes
+	}
+	if redact {
+		return append(changes, configChange{path: path, old: redactedJSON, new: redactedJSON})
+	}
+
+	return append(changes, configChange{path: path, old: marshalRedacted(old, nil, 0), new: marshalRedacted(new, nil, 0)})
+}
+
+// interfaceOf returns the value held by v, nil for an invalid value.
+func interfaceOf(v reflect.Value) interface{} {
+	if !v.IsValid() {
+		return nil
+	}
+
+	return v.Interface()
+}
@@ -0,0 +1,71 @@
+package zerolog_wrapper
+
+import (
+	"bytes"
+	"encoding/json"
+	"testing"
+)
+
+type diffDB struct {
+	Host     string `json:"host"`
+	Password string `json:"password" log:"redact"`
+}
+
+type diffConfig struct {
+	Name     string            `json:"name"`
+	Token    string            `json:"token" log:"redact"`
+	DB       *diffDB           `json:"db"`
+	Replicas []diffDB          `json:"replicas"`
+	Shards   map[string]diffDB `json:"shards"`
+	Any      interface{}       `json:"any"`
+}
+
+func TestConfigDiff(t *testing.T) {
+	buf := useStd(t)
+
+	ConfigDiff(diffConfig{Name: "a", Token: "t1"}, diffConfig{Name: "b", Token: "t2"})
+
+	var event struct {
+		Changes map[string]map[string]interface{} `json:"changes"`
+	}
+	if err := json.Unmarshal(buf.Bytes(), &event); err != nil {
+		t.Fatalf("%v: %s", err, buf.String())
+	}
+	if got := event.Changes["name"]; got["old"] != "a" || got["new"] != "b" {
+		t.Errorf("name change = %v", got)
+	}
+	if got := event.Changes["token"]; got["old"] != redactedValue || got["new"] != redactedValue {
+		t.Errorf("token change = %v", got)
+	}
+
+	buf.Reset()
+	ConfigDiff(diffConfig{}, diffConfig{})
+	if buf.Len() != 0 {
+		t.Errorf("logged without changes: %s", buf.String())
+	}
+}
+
+func TestConfigDiffRedactsInsideChangedValues(t *testing.T) {
+	buf := useStd(t)
+
+	ConfigDiff(diffConfig{}, diffConfig{
+		DB:       &diffDB{Host: "h", Password: "s3cret"},
+		Replicas: []diffDB{{Host: "r", Password: "s3cret2"}},
+		Shards:   map[string]diffDB{"eu": {Host: "s", Password: "s3cret3"}},
+		Any:      &diffDB{Host: "x", Password: "s3cret4"},
+	})
+
+	if bytes.Contains(buf.Bytes(), []byte("s3cret")) {
+		t.Fatalf("redacted field logged in clear: %s", buf.String())
+	}
+	for _, want := range []string{
+		`"db":{"old":null,"new":{"host":"h","password":"[REDACTED]"}}`,
+		`"replicas":{"old":null,"new":[{"host":"r","password":"[REDACTED]"}]}`,
+		`"shards":{"old":null,"new":{"eu":{"host":"s","password":"[REDACTED]"}}}`,
+		`"any":{"old":null,"new":{"host":"x","password":"[REDACTED]"}}`,
+	} {
+		if !bytes.Contains(buf.Bytes(), []byte(want)) {
+			t.Errorf("missing %s in %s", want, buf.String())
+		}
+	}
+}
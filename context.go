@@ -0,0 +1,228 @@
+package zerolog_wrapper
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"sync"
+
+	"github.com/rs/zerolog"
+)
+
+type loggerKey struct{}
+
+// contextEntry is the logger stored in a context by WithContext.
+type contextEntry struct {
+	logger zerolog.Logger
+
+	once sync.Once
+	// fields are the context fields of logger, without those added by hooks
+	fields []jsonField
+}
+
+// contextFields returns the context fields of the entry's logger, only rendering the logger on the first call.
+func (ce *contextEntry) contextFields() []jsonField {
+	ce.once.Do(func() {
+		ce.fields = ownContextFields(ce.logger)
+	})
+
+	return ce.fields
+}
+
+type levelKey struct{}
+
+// WithContext returns a copy of ctx carrying l, to be retrieved with FromContext.
+// When ctx already carries a logger, the context fields of that logger missing
+// from l are added to it, so nested middlewares can each store a logger with
+// their own fields and FromContext sees all of them.
+//
+// eg:
+//
+//	ctx = log.WithContext(ctx, log.GetLogger().With().Str("tenant", tenant).Logger())
+//	// later, in a nested middleware
+//	ctx = log.WithContext(ctx, log.GetLogger().With().Str("route", route).Logger())
+//	log.FromContext(ctx).Info().Msg("carries tenant and route")
+func WithContext(ctx context.Context, l zerolog.Logger) context.Context {
+	entry := &contextEntry{logger: l}
+	if parent, ok := ctx.Value(loggerKey{}).(*contextEntry); ok {
+		l, fields := mergeContextFields(l, parent.contextFields())
+		entry.logger = l
+		entry.once.Do(func() { entry.fields = fields })
+	}
+
+	return context.WithValue(ctx, loggerKey{}, entry)
+}
+
+var (
+	hookFieldsMu sync.RWMutex
+	// hookFieldNames are added to every event by hooks rather than stored in the logger context
+	hookFieldNames = map[string]bool{
+		zerolog.TimestampFieldName: true,
+		zerolog.LevelFieldName:     true,
+		zerolog.MessageFieldName:   true,
+		zerolog.CallerFieldName:    true,
+		"host_ip":                  true,
+	}
+)
+
+// registerHookField records that a hook adds name to events, so Context and
+// WithContext don't mistake it for a context field. Options installing such a
+// hook call it when they are applied.
+func registerHookField(name string) {
+	hookFieldsMu.Lock()
+	defer hookFieldsMu.Unlock()
+
+	hookFieldNames[name] = true
+}
+
+// isHookField reports whether key is added to every event by a hook.
+func isHookField(key string) bool {
+	if key == PrefixFieldName {
+		p, _ := prefix.Load().(string)
+		return p != ""
+	}
+
+	hookFieldsMu.RLock()
+	hook := hookFieldNames[key]
+	hookFieldsMu.RUnlock()
+
+	return hook || isDynamicField(key)
+}
+
+// mergeContextFields adds the parent fields which l lacks to l, returning
+// the merged logger and its context fields.
+func mergeContextFields(l zerolog.Logger, parent []jsonField) (zerolog.Logger, []jsonField) {
+	fields := ownContextFields(l)
+	own := make(map[string]bool, len(fields))
+	for _, field := range fields {
+		own[field.key] = true
+	}
+
+	var missing []jsonField
+	for _, field := range parent {
+		if !own[field.key] {
+			missing = append(missing, field)
+		}
+	}
+	if len(missing) == 0 {
+		return l, fields
+	}
+
+	c := l.With()
+	for _, field := range missing {
+		c = c.RawJSON(field.key, field.value)
+	}
+
+	return c.Logger(), append(fields, missing...)
+}
+
+// ownContextFields returns the context fields of l without the fields added by hooks.
+func ownContextFields(l zerolog.Logger) []jsonField {
+	var fields []jsonField
+	for _, field := range contextFields(l) {
+		if !isHookField(field.key) {
+			fields = append(fields, field)
+		}
+	}
+
+	return fields
+}
+
+// contextRenderMessage is the message of the empty events rendered by
+// contextFields. Hooks with side effects or costs, such as filters and
+// dynamic fields, skip these events, see isContextRender.
+const contextRenderMessage = "\x00zerolog_wrapper context fields"
+
+// isContextRender reports whether msg belongs to an event rendered by contextFields.
+func isContextRender(msg string) bool {
+	return msg == contextRenderMessage
+}
+
+// contextFields returns the fields of l, zerolog doesn't expose its context
+// so they are taken from an empty event written to a buffer.
+func contextFields(l zerolog.Logger) []jsonField {
+	var buf bytes.Buffer
+	r := l.Output(&buf).Sample(nil)
+	r.Log().Msg(contextRenderMessage)
+
+	fields, _ := decodeFields(buf.Bytes())
+
+	return fields
+}
+
+// Context returns the context fields of the global logger, eg: those added
+// through UpdateContext, for inspection or to echo them on a health endpoint.
+// Fields added to each event by hooks, such as time and host_ip, are left out.
+func Context() map[string]interface{} {
+	return std.Context()
+}
+
+// Context returns the context fields of the instance, see Context.
+func (i *Instance) Context() map[string]interface{} {
+	l := i.current()
+
+	fields := make(map[string]interface{})
+	for _, field := range ownContextFields(l) {
+		var value interface{}
+		if err := json.Unmarshal(field.value, &value); err == nil {
+			fields[field.key] = value
+		}
+	}
+
+	return fields
+}
+
+// ContextWithLevel returns a copy of ctx whose events, when logged through
+// FromContext, use level instead of the level of the logger. This allows eg:
+// debug logging for the requests of a single tenant only.
+func ContextWithLevel(ctx context.Context, level LogLevel) context.Context {
+	return context.WithValue(ctx, levelKey{}, level)
+}
+
+// FromContext returns the logger stored in ctx by WithContext, falling back to
+// the global logger. A level stored by ContextWithLevel overrides the level of
+// the returned logger and a span started by StartSpan adds its span_id.
+//
+// eg:
+//
+//	log.FromContext(ctx).Debug().Msg("only logged for verbose contexts")
+func FromContext(ctx context.Context) *zerolog.Logger {
+	l := contextLogger(ctx)
+
+	if level, ok := ctx.Value(levelKey{}).(LogLevel); ok {
+		l = l.Level(toZerologLevel(level))
+	}
+
+	if span, ok := spanFromContext(ctx); ok {
+		l = withSpanID(ctx, l, span.id)
+	}
+
+	return &l
+}
+
+// contextLogger returns the logger stored in ctx, or the global logger, without any level override.
+func contextLogger(ctx context.Context) zerolog.Logger {
+	if entry, ok := ctx.Value(loggerKey{}).(*contextEntry); ok {
+		return entry.logger
+	}
+
+	return current()
+}
+
+// withSpanID returns l, the logger of ctx, carrying id as its span_id. The
+// logger stored in ctx already carries it when it was taken from FromContext
+// after StartSpan, then l is returned as is. zerolog can't replace a context
+// field, so a stored logger carrying the span_id of an enclosing span gets a
+// second span_id, the later one being id.
+func withSpanID(ctx context.Context, l zerolog.Logger, id string) zerolog.Logger {
+	if entry, ok := ctx.Value(loggerKey{}).(*contextEntry); ok {
+		quoted, _ := json.Marshal(id)
+		for _, field := range entry.contextFields() {
+			if field.key == "span_id" && bytes.Equal(field.value, quoted) {
+				return l
+			}
+		}
+	}
+
+	return l.With().Str("span_id", id).Logger()
+}
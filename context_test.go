@@ -0,0 +1,104 @@
+package zerolog_wrapper
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync/atomic"
+	"testing"
+
+	"github.com/rs/zerolog"
+)
+
+func TestContextLeavesOutHookFields(t *testing.T) {
+	l, err := New(InfoLevel, Prod, DisableHostIP(), WithOutput(&bytes.Buffer{}), WithElapsed())
+	if err != nil {
+		t.Fatal(err)
+	}
+	l.UpdateContext(func(c zerolog.Context) zerolog.Context { return c.Str("service", "billing") })
+
+	fields := l.Context()
+	if _, ok := fields["elapsed_ms"]; ok {
+		t.Errorf("Context() contains elapsed_ms: %v", fields)
+	}
+	if fields["service"] != "billing" {
+		t.Errorf("Context() lacks service: %v", fields)
+	}
+}
+
+func TestWithContextDoesNotCopyHookFields(t *testing.T) {
+	var buf bytes.Buffer
+	l, err := New(InfoLevel, Prod, DisableHostIP(), WithOutput(&buf), WithElapsed())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	base := l.GetLogger()
+	ctx := WithContext(context.Background(), base.With().Str("tenant", "t1").Logger())
+	ctx = WithContext(ctx, base.With().Str("route", "/x").Logger())
+	FromContext(ctx).Info().Msg("nested")
+
+	if got := strings.Count(buf.String(), `"elapsed_ms":`); got != 1 {
+		t.Errorf("elapsed_ms written %d times: %s", got, buf.String())
+	}
+}
+
+func TestWithContextTwoLevelMiddleware(t *testing.T) {
+	var buf bytes.Buffer
+	l, err := New(InfoLevel, Prod, DisableHostIP(), WithOutput(&buf))
+	if err != nil {
+		t.Fatal(err)
+	}
+	base := l.GetLogger()
+
+	tenant := func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			ctx := WithContext(r.Context(), base.With().Str("tenant", "t1").Logger())
+			next.ServeHTTP(w, r.WithContext(ctx))
+		})
+	}
+	route := func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			ctx := WithContext(r.Context(), base.With().Str("route", r.URL.Path).Logger())
+			next.ServeHTTP(w, r.WithContext(ctx))
+		})
+	}
+	handler := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		FromContext(r.Context()).Info().Msg("handled")
+	})
+
+	tenant(route(handler)).ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/orders", nil))
+
+	var event map[string]interface{}
+	if err := json.Unmarshal(buf.Bytes(), &event); err != nil {
+		t.Fatalf("%v: %s", err, buf.String())
+	}
+	if event["tenant"] != "t1" || event["route"] != "/orders" {
+		t.Errorf("fields of both middlewares expected: %s", buf.String())
+	}
+}
+
+func TestWithContextSkipsHookSideEffects(t *testing.T) {
+	l, err := New(InfoLevel, Prod, DisableHostIP(), WithOutput(&bytes.Buffer{}))
+	if err != nil {
+		t.Fatal(err)
+	}
+	base := l.GetLogger()
+
+	previous, _ := dynamicFields.Load().([]dynamicField)
+	defer dynamicFields.Store(previous)
+
+	var calls atomic.Int32
+	RegisterDynamicField("calls", func() interface{} { return calls.Add(1) })
+
+	ctx := WithContext(context.Background(), base.With().Str("a", "1").Logger())
+	ctx = WithContext(ctx, base.With().Str("b", "2").Logger())
+	_ = WithContext(ctx, base.With().Str("c", "3").Logger())
+
+	if n := calls.Load(); n != 0 {
+		t.Errorf("dynamic field evaluated %d times by WithContext", n)
+	}
+}
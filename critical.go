@@ -0,0 +1,96 @@
+package zerolog_wrapper
+
+import (
+	"errors"
+	"io"
+
+	"github.com/rs/zerolog"
+)
+
+// syncer is implemented by outputs which can commit written data to stable storage, like *os.File.
+type syncer interface {
+	Sync() error
+}
+
+// Critical starts a new message with error level and critical set to true,
+// for the rare events which must not be lost, eg: a financial transaction.
+// Msg doesn't return before the event is written, every buffering writer
+// (WithBuffer, WithAsync, WithGzip) is flushed and each output given through
+// WithOutput or WithOutputs which has a Sync method, like *os.File, is synced.
+// Critical events are never sampled.
+//
+// This is slow by design: each event costs a flush of the whole pipeline and
+// an fsync, typically milliseconds on disk, so don't use it on hot paths.
+// Write and sync failures go to zerolog.ErrorHandler.
+//
+// You must call Msg on the returned event in order to send the event.
+func Critical() *zerolog.Event {
+	return std.Critical()
+}
+
+// Critical starts a new message like Critical, flushing and syncing the instance's outputs.
+//
+// The events of a Clone go through the output of the instance it was cloned
+// from, so until SetOutput gave the Clone its own output the writers of that
+// instance are flushed and synced.
+//
+// You must call Msg on the returned event in order to send the event.
+func (i *Instance) Critical() *zerolog.Event {
+	l := i.current()
+
+	i.mu.RLock()
+	out, writers := i.output, i.outputWriters()
+	i.mu.RUnlock()
+	if out != nil {
+		l = l.Output(criticalWriter{out: out, lifecycle: writers}).Sample(nil)
+	}
+
+	return l.Error().Bool("critical", true)
+}
+
+// criticalWriter writes an event to out, then flushes and syncs every output of lifecycle.
+type criticalWriter struct {
+	out       zerolog.LevelWriter
+	lifecycle *lifecycle
+}
+
+func (w criticalWriter) Write(p []byte) (int, error) {
+	return w.WriteLevel(zerolog.NoLevel, p)
+}
+
+func (w criticalWriter) WriteLevel(level zerolog.Level, p []byte) (int, error) {
+	n, err := w.out.WriteLevel(level, p)
+	if err != nil {
+		return n, err
+	}
+
+	return n, errors.Join(w.lifecycle.flush(), w.lifecycle.sync())
+}
+
+// registerSyncer adds w to the outputs synced after a critical event, if it can be synced.
+func (lc *lifecycle) registerSyncer(w io.Writer) {
+	s, ok := w.(syncer)
+	if !ok {
+		return
+	}
+
+	lc.mu.Lock()
+	defer lc.mu.Unlock()
+
+	lc.syncers = append(lc.syncers, s)
+}
+
+func (lc *lifecycle) sync() error {
+	lc.mu.Lock()
+	ss := append([]syncer(nil), lc.syncers...)
+	lc.mu.Unlock()
+
+	var errs []error
+	for _, s := range ss {
+		if err := s.Sync(); err != nil {
+			errs = append(errs, err)
+		}
+	}
+
+	return errors.Join(errs...)
+}
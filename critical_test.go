@@ -0,0 +1,60 @@
+package zerolog_wrapper
+
+import (
+	"bytes"
+	"testing"
+)
+
+// syncBuffer is a bytes.Buffer counting the calls of its Sync method.
+type syncBuffer struct {
+	bytes.Buffer
+	syncs int
+}
+
+func (b *syncBuffer) Sync() error {
+	b.syncs++
+	return nil
+}
+
+func TestCritical(t *testing.T) {
+	var buf syncBuffer
+	l, err := New(InfoLevel, Prod, DisableHostIP(), WithOutput(&buf), WithAsync(16))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	l.Critical().Msg("must not be lost")
+
+	if !bytes.Contains(buf.Bytes(), []byte(`"critical":true`)) {
+		t.Errorf("event not written when Msg returned: %q", buf.String())
+	}
+	if buf.syncs != 1 {
+		t.Errorf("output synced %d times, want 1", buf.syncs)
+	}
+}
+
+func TestCriticalOnClone(t *testing.T) {
+	var buf syncBuffer
+	l, err := New(InfoLevel, Prod, DisableHostIP(), WithOutput(&buf), WithAsync(16))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	clone := l.Clone()
+	clone.Critical().Msg("from the clone")
+
+	if !bytes.Contains(buf.Bytes(), []byte(`"message":"from the clone"`)) {
+		t.Errorf("event not written when Msg returned: %q", buf.String())
+	}
+	if buf.syncs != 1 {
+		t.Errorf("output synced %d times, want 1", buf.syncs)
+	}
+
+	var own syncBuffer
+	clone.SetOutput(&own)
+	clone.Critical().Msg("to its own output")
+
+	if buf.syncs != 1 || own.syncs != 1 {
+		t.Errorf("synced %d and %d times, want only the clone's own output", buf.syncs, own.syncs)
+	}
+}
@@ -0,0 +1,28 @@
+package zerolog_wrapper
+
+import (
+	"context"
+	"time"
+
+	"github.com/rs/zerolog"
+)
+
+// Deadline adds the time left until the deadline of ctx as
+// deadline_remaining_ms, negative once it has passed, or no_deadline set to
+// true when ctx has none. Logging it at each stage of a request shows how
+// close to its time budget the request ran.
+//
+// eg:
+//
+//	log.Info().Func(log.Deadline(ctx)).Msg("calling payment service")
+func Deadline(ctx context.Context) func(e *zerolog.Event) {
+	return func(e *zerolog.Event) {
+		deadline, ok := ctx.Deadline()
+		if !ok {
+			e.Bool("no_deadline", true)
+			return
+		}
+
+		e.Int64("deadline_remaining_ms", time.Until(deadline).Milliseconds())
+	}
+}
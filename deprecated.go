@@ -0,0 +1,45 @@
+package zerolog_wrapper
+
+import (
+	"runtime"
+	"strconv"
+	"sync"
+)
+
+// deprecationsSeen holds the call sites DeprecatedOnce has warned about.
+var deprecationsSeen sync.Map
+
+// deprecationSite identifies a call of DeprecatedOnce together with the code calling the deprecated function.
+type deprecationSite struct {
+	deprecated, caller uintptr
+}
+
+// DeprecatedOnce logs msg at warn level the first time a deprecated function is
+// called from a given call site and is a no-op for that call site afterwards, no
+// matter which goroutine calls it. The call site is logged as call_site, its
+// path shortened like the one of the caller field.
+//
+// eg:
+//
+//	func OldAPI() {
+//		log.DeprecatedOnce("OldAPI is deprecated, use NewAPI instead")
+//		...
+//	}
+func DeprecatedOnce(msg string) {
+	deprecated, _, _, ok := runtime.Caller(1)
+	if !ok {
+		return
+	}
+	caller, file, line, ok := runtime.Caller(2)
+	if !ok {
+		return
+	}
+	if _, seen := deprecationsSeen.LoadOrStore(deprecationSite{deprecated, caller}, struct{}{}); seen {
+		return
+	}
+
+	Warn().
+		Bool("deprecated", true).
+		Str("call_site", trimCallerPath(file)+":"+strconv.Itoa(line)).
+		Msg(msg)
+}
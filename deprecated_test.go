@@ -0,0 +1,35 @@
+package zerolog_wrapper
+
+import (
+	"bytes"
+	"encoding/json"
+	"regexp"
+	"testing"
+)
+
+func deprecatedFunc() {
+	DeprecatedOnce("deprecatedFunc is deprecated")
+}
+
+func TestDeprecatedOnce(t *testing.T) {
+	buf := useStd(t)
+
+	for n := 0; n < 3; n++ {
+		deprecatedFunc()
+	}
+
+	if got := bytes.Count(buf.Bytes(), []byte("\n")); got != 1 {
+		t.Fatalf("got %d warnings, want 1:\n%s", got, buf.String())
+	}
+
+	var event map[string]interface{}
+	if err := json.Unmarshal(buf.Bytes(), &event); err != nil {
+		t.Fatal(err)
+	}
+	if event["deprecated"] != true || event["level"] != "warn" {
+		t.Errorf("unexpected event: %s", buf.String())
+	}
+	if site, _ := event["call_site"].(string); !regexp.MustCompile(`^deprecated_test\.go:\d+$`).MatchString(site) {
+		t.Errorf("call_site = %q, want the trimmed path of the caller", site)
+	}
+}
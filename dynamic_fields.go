@@ -0,0 +1,69 @@
+package zerolog_wrapper
+
+import (
+	"sync"
+	"sync/atomic"
+
+	"github.com/rs/zerolog"
+)
+
+type dynamicField struct {
+	key string
+	fn  func() interface{}
+}
+
+var (
+	dynamicFieldsMu sync.Mutex
+	// dynamicFields holds the []dynamicField registered by RegisterDynamicField
+	dynamicFields atomic.Value
+)
+
+// RegisterDynamicField adds key to every event with the value fn returns at
+// the time the event is logged, eg: the current queue depth. Registering a
+// key again replaces its fn.
+//
+// fn runs synchronously for every enabled event of every logger, so keep it
+// cheap and safe for concurrent use, eg: an atomic load. Prefer UpdateContext
+// for values which don't change.
+//
+// eg:
+//
+//	log.RegisterDynamicField("queue_depth", func() interface{} { return queue.Len() })
+func RegisterDynamicField(key string, fn func() interface{}) {
+	dynamicFieldsMu.Lock()
+	defer dynamicFieldsMu.Unlock()
+
+	current, _ := dynamicFields.Load().([]dynamicField)
+	fields := make([]dynamicField, 0, len(current)+1)
+	for _, field := range current {
+		if field.key != key {
+			fields = append(fields, field)
+		}
+	}
+	dynamicFields.Store(append(fields, dynamicField{key: key, fn: fn}))
+}
+
+// isDynamicField reports whether key was registered through RegisterDynamicField.
+func isDynamicField(key string) bool {
+	fields, _ := dynamicFields.Load().([]dynamicField)
+	for _, field := range fields {
+		if field.key == key {
+			return true
+		}
+	}
+
+	return false
+}
+
+// dynamicFieldsHook adds the fields registered through RegisterDynamicField.
+type dynamicFieldsHook struct{}
+
+func (dynamicFieldsHook) Run(e *zerolog.Event, level zerolog.Level, msg string) {
+	if isContextRender(msg) {
+		return
+	}
+	fields, _ := dynamicFields.Load().([]dynamicField)
+	for _, field := range fields {
+		e.Interface(field.key, field.fn())
+	}
+}
@@ -0,0 +1,23 @@
+package zerolog_wrapper
+
+import (
+	"time"
+
+	"github.com/rs/zerolog"
+)
+
+// processStart is taken when the package is initialized, it carries the monotonic clock reading.
+var processStart = time.Now()
+
+// WithElapsed adds the milliseconds since the process started as elapsed_ms
+// to every event, eg: to follow the timing of a single run program in the dev
+// console output without reading timestamps. It is measured on the monotonic
+// clock, so changes of the wall clock don't affect it.
+func WithElapsed() Option {
+	return func(o *options) {
+		registerHookField("elapsed_ms")
+		o.hooks = append(o.hooks, zerolog.HookFunc(func(e *zerolog.Event, level zerolog.Level, msg string) {
+			e.Int64("elapsed_ms", time.Since(processStart).Milliseconds())
+		}))
+	}
+}
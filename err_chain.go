@@ -0,0 +1,39 @@
+package zerolog_wrapper
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/rs/zerolog"
+)
+
+// maxErrChainDepth bounds the layers logged by ErrChain.
+const maxErrChainDepth = 32
+
+// ErrChain adds the unwrap chain of err under error_chain, one object with the
+// message and the type of each layer, outermost first.
+//
+// Use it with the Func method of an event:
+//
+//	err := fmt.Errorf("load config: %w", &fs.PathError{Op: "open", Path: "app.yaml", Err: fs.ErrNotExist})
+//	log.Error().Err(err).Func(log.ErrChain(err)).Msg("startup failed")
+//	// Output: {"level":"error","error":"load config: open app.yaml: file does not exist",
+//	//   "error_chain":[{"message":"load config: open app.yaml: file does not exist","type":"*fmt.wrapError"},
+//	//   {"message":"open app.yaml: file does not exist","type":"*fs.PathError"},
+//	//   {"message":"file does not exist","type":"*errors.errorString"}],"message":"startup failed"}
+func ErrChain(err error) func(e *zerolog.Event) {
+	return func(e *zerolog.Event) {
+		if err == nil {
+			return
+		}
+
+		arr := zerolog.Arr()
+		for layer, depth := err, 0; layer != nil && depth < maxErrChainDepth; layer, depth = errors.Unwrap(layer), depth+1 {
+			arr.Dict(zerolog.Dict().
+				Str("message", layer.Error()).
+				Str("type", fmt.Sprintf("%T", layer)))
+		}
+
+		e.Array("error_chain", arr)
+	}
+}
@@ -0,0 +1,55 @@
+package zerolog_wrapper
+
+import (
+	"errors"
+
+	"github.com/rs/zerolog"
+)
+
+// ErrorFielder is implemented by errors carrying structured context, as
+// attached by some error libraries. Err adds these fields to the event.
+type ErrorFielder interface {
+	Fields() map[string]interface{}
+}
+
+// Err adds err to the event like the Err method of an event and merges the
+// fields of every error in its unwrap chain which implements ErrorFielder.
+// When layers define the same key, the outermost one wins.
+//
+// Use it with the Func method of an event:
+//
+//	log.Error().Func(log.Err(err)).Msg("charge failed")
+//	// Output: {"level":"error","error":"charge: card declined","order_id":"o-17","message":"charge failed"}
+func Err(err error) func(e *zerolog.Event) {
+	return func(e *zerolog.Event) {
+		if err == nil {
+			return
+		}
+
+		e.Err(err)
+		if fields := errorFields(err); len(fields) > 0 {
+			e.Fields(fields)
+		}
+	}
+}
+
+// errorFields collects the fields of the ErrorFielder layers of err, outer layers taking precedence.
+func errorFields(err error) map[string]interface{} {
+	var fields map[string]interface{}
+	for layer, depth := err, 0; layer != nil && depth < maxErrChainDepth; layer, depth = errors.Unwrap(layer), depth+1 {
+		fielder, ok := layer.(ErrorFielder)
+		if !ok {
+			continue
+		}
+		for key, value := range fielder.Fields() {
+			if fields == nil {
+				fields = make(map[string]interface{})
+			}
+			if _, ok := fields[key]; !ok {
+				fields[key] = value
+			}
+		}
+	}
+
+	return fields
+}
@@ -0,0 +1,97 @@
+package zerolog_wrapper
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net"
+	"os"
+	"strconv"
+	"sync/atomic"
+
+	"github.com/rs/zerolog"
+)
+
+const (
+	ErrorClassValidation = "validation"
+	ErrorClassTimeout    = "timeout"
+	ErrorClassInternal   = "internal"
+	ErrorClassExternal   = "external"
+)
+
+// retryableErrorClasses are the classes logged with is_retryable set to true.
+var retryableErrorClasses = map[string]bool{
+	ErrorClassTimeout:  true,
+	ErrorClassExternal: true,
+}
+
+// errorClassifier holds the func(error) string set by SetErrorClassifier.
+var errorClassifier atomic.Value
+
+// SetErrorClassifier replaces the classifier used by ClassifiedError for
+// errors logged without a class, eg: to map the errors of a database driver.
+// An empty class returned by classify falls back to ClassifyError, a nil
+// classify restores the default.
+func SetErrorClassifier(classify func(err error) string) {
+	if classify == nil {
+		classify = ClassifyError
+	}
+	errorClassifier.Store(classify)
+}
+
+// ClassifyError is the default classifier of ClassifiedError. Deadlines and
+// network timeouts are timeout errors, other network errors are external and
+// parse errors are validation errors. Anything else is internal.
+func ClassifyError(err error) string {
+	var netErr net.Error
+	var numErr *strconv.NumError
+	var syntaxErr *json.SyntaxError
+	var typeErr *json.UnmarshalTypeError
+
+	switch {
+	case errors.Is(err, context.DeadlineExceeded), errors.Is(err, os.ErrDeadlineExceeded):
+		return ErrorClassTimeout
+	case errors.As(err, &netErr):
+		if netErr.Timeout() {
+			return ErrorClassTimeout
+		}
+		return ErrorClassExternal
+	case errors.As(err, &numErr), errors.As(err, &syntaxErr), errors.As(err, &typeErr):
+		return ErrorClassValidation
+	default:
+		return ErrorClassInternal
+	}
+}
+
+// classifyError returns the class of err from the configured classifier.
+func classifyError(err error) string {
+	if classify, ok := errorClassifier.Load().(func(error) string); ok {
+		if class := classify(err); class != "" {
+			return class
+		}
+	}
+
+	return ClassifyError(err)
+}
+
+// ClassifiedError starts a new message with error level carrying err with
+// its fields (see Err), error_class and is_retryable, which is true for
+// timeout and external errors. An empty class is derived from err through
+// the classifier set by SetErrorClassifier.
+//
+// eg:
+//
+//	log.ClassifiedError(err, log.ErrorClassValidation).Str("field", "email").Msg("invalid signup")
+//	log.ClassifiedError(err, "").Msg("payment failed")
+//
+// You must call Msg on the returned event in order to send the event.
+func ClassifiedError(err error, class string) *zerolog.Event {
+	if class == "" {
+		class = classifyError(err)
+	}
+
+	return Error().
+		Func(Err(err)).
+		Str("error_class", class).
+		Bool("is_retryable", retryableErrorClasses[class])
+}
@@ -0,0 +1,26 @@
+package zerolog_wrapper
+
+import "github.com/rs/zerolog"
+
+// Handled starts a new message with error level carrying err, with its fields
+// (see Err), and error_disposition set to "handled", for errors which were
+// expected and recovered from, eg: a retried request. Dashboards and alerts
+// can then tell them apart from the errors logged by Unhandled.
+//
+// eg:
+//
+//	log.Handled(err).Msg("cache miss, reading from the database")
+//
+// You must call Msg on the returned event in order to send the event.
+func Handled(err error) *zerolog.Event {
+	return Error().Func(Err(err)).Str("error_disposition", "handled")
+}
+
+// Unhandled starts a new message with error level like Handled, with
+// error_disposition set to "unhandled", for errors which propagate to the
+// caller or end the operation.
+//
+// You must call Msg on the returned event in order to send the event.
+func Unhandled(err error) *zerolog.Event {
+	return Error().Func(Err(err)).Str("error_disposition", "unhandled")
+}
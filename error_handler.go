@@ -0,0 +1,25 @@
+package zerolog_wrapper
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/rs/zerolog"
+)
+
+// SetInternalErrorHandler sets the function zerolog calls when it fails to
+// write an event, eg: because the output returned an error. Such failures
+// are otherwise easy to miss as the event is lost. A nil handler restores
+// the default, which writes a short message to stderr.
+//
+// zerolog keeps the handler globally, set it before logging starts.
+func SetInternalErrorHandler(handler func(err error)) {
+	if handler == nil {
+		handler = defaultInternalErrorHandler
+	}
+	zerolog.ErrorHandler = handler
+}
+
+func defaultInternalErrorHandler(err error) {
+	fmt.Fprintf(os.Stderr, "zerolog_wrapper: could not write event: %v\n", err)
+}
@@ -0,0 +1,85 @@
+package zerolog_wrapper
+
+import (
+	"path/filepath"
+	"runtime"
+	"strconv"
+	"strings"
+
+	"github.com/rs/zerolog"
+)
+
+// maxErrorStackFrames caps the frames captured by WithErrorStacks.
+const maxErrorStackFrames = 64
+
+// WithErrorStacks adds the stack of the logging goroutine under stack to
+// every error, fatal and panic event, so the stack of a failure is at hand
+// without calling Stack on each event and without capturing stacks for lower
+// levels. The frames are written like those of zerolog's pkgerrors marshaler,
+// starting at the function which logged the event. Events already carrying
+// a stack, eg: from Stack and Err, keep their own one.
+func WithErrorStacks() Option {
+	return func(o *options) {
+		o.hooks = append(o.hooks, errorStackHook{})
+		o.transforms = append(o.transforms, firstErrorStack)
+	}
+}
+
+// firstErrorStack drops the stack added by errorStackHook when the event
+// already had one. Hooks run when the event is sent, so that stack is always the later one.
+func firstErrorStack(fields []jsonField) []jsonField {
+	kept := fields[:0]
+	seen := false
+	for _, field := range fields {
+		if field.key == zerolog.ErrorStackFieldName {
+			if seen {
+				continue
+			}
+			seen = true
+		}
+		kept = append(kept, field)
+	}
+
+	return kept
+}
+
+type errorStackHook struct{}
+
+func (errorStackHook) Run(e *zerolog.Event, level zerolog.Level, msg string) {
+	if level < zerolog.ErrorLevel || level > zerolog.PanicLevel {
+		return
+	}
+
+	pcs := make([]uintptr, maxErrorStackFrames)
+	frames := runtime.CallersFrames(pcs[:runtime.Callers(2, pcs)])
+
+	stack := zerolog.Arr()
+	logging := true
+	for {
+		frame, more := frames.Next()
+		// skip the frames of zerolog and of the logger itself
+		if logging && isLoggerFrame(frame.Function) {
+			if !more {
+				break
+			}
+			continue
+		}
+		logging = false
+
+		stack = stack.Dict(zerolog.Dict().
+			Str("func", frame.Function[strings.LastIndexByte(frame.Function, '/')+1:]).
+			Str("line", strconv.Itoa(frame.Line)).
+			Str("source", filepath.Base(frame.File)))
+		if !more {
+			break
+		}
+	}
+
+	e.Array(zerolog.ErrorStackFieldName, stack)
+}
+
+// isLoggerFrame reports whether function belongs to zerolog or to this package.
+func isLoggerFrame(function string) bool {
+	return strings.HasPrefix(function, "github.com/rs/zerolog.") ||
+		strings.HasPrefix(function, "github.com/ashokrajar/zerolog_wrapper.")
+}
@@ -0,0 +1,50 @@
+package zerolog_wrapper
+
+import (
+	"bytes"
+	"errors"
+	"testing"
+
+	"github.com/rs/zerolog"
+)
+
+func TestWithErrorStacks(t *testing.T) {
+	var buf bytes.Buffer
+	l, err := New(InfoLevel, Prod, DisableHostIP(), WithOutput(&buf), WithErrorStacks())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	l.Error().Msg("failed")
+	// the frames of this package are skipped as logger frames, tests included
+	if !bytes.Contains(buf.Bytes(), []byte(`"stack":[{"func":"testing.tRunner"`)) {
+		t.Errorf("stack missing: %s", buf.String())
+	}
+
+	buf.Reset()
+	l.Info().Msg("fine")
+	if bytes.Contains(buf.Bytes(), []byte(`"stack"`)) {
+		t.Errorf("info event got a stack: %s", buf.String())
+	}
+}
+
+func TestWithErrorStacksKeepsExistingStack(t *testing.T) {
+	previous := zerolog.ErrorStackMarshaler
+	zerolog.ErrorStackMarshaler = func(err error) interface{} { return "from Err" }
+	t.Cleanup(func() { zerolog.ErrorStackMarshaler = previous })
+
+	var buf bytes.Buffer
+	l, err := New(InfoLevel, Prod, DisableHostIP(), WithOutput(&buf), WithErrorStacks())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	l.Error().Stack().Err(errors.New("boom")).Msg("failed")
+
+	if got := bytes.Count(buf.Bytes(), []byte(`"stack":`)); got != 1 {
+		t.Fatalf("got %d stack fields, want 1: %s", got, buf.String())
+	}
+	if !bytes.Contains(buf.Bytes(), []byte(`"stack":"from Err"`)) {
+		t.Errorf("the event's own stack was replaced: %s", buf.String())
+	}
+}
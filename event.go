@@ -0,0 +1,49 @@
+package zerolog_wrapper
+
+import (
+	"sync"
+
+	"github.com/rs/zerolog"
+)
+
+var (
+	eventsMu sync.RWMutex
+	events   = map[string]struct{}{}
+)
+
+// RegisterEvents adds names to the vocabulary of event types accepted by Event.
+//
+// eg:
+//
+//	log.RegisterEvents("user.login", "payment.failed")
+func RegisterEvents(names ...string) {
+	eventsMu.Lock()
+	defer eventsMu.Unlock()
+
+	for _, name := range names {
+		events[name] = struct{}{}
+	}
+}
+
+// isRegisteredEvent reports whether name was registered through RegisterEvents.
+func isRegisteredEvent(name string) bool {
+	eventsMu.RLock()
+	defer eventsMu.RUnlock()
+
+	_, ok := events[name]
+	return ok
+}
+
+// Event starts a new message with info level carrying name as the "event" field.
+//
+// In the dev environment a warning is logged when name was not registered
+// through RegisterEvents, so typos are caught before they reach downstream aggregation.
+//
+// You must call Msg on the returned event in order to send the event.
+func Event(name string) *zerolog.Event {
+	if currentEnv() == Dev && !isRegisteredEvent(name) {
+		Warn().Str("event", name).Msg("unregistered event name")
+	}
+
+	return Info().Str("event", name)
+}
@@ -0,0 +1,6 @@
+package zerolog_wrapper
+
+import "errors"
+
+// ErrEventLogUnsupported is returned by InitLogWithEventLog on platforms other than Windows.
+var ErrEventLogUnsupported = errors.New("zerolog_wrapper: the Windows Event Log is only available on windows")
@@ -0,0 +1,9 @@
+//go:build !windows
+
+package zerolog_wrapper
+
+// InitLogWithEventLog is only supported on Windows, elsewhere it always
+// returns ErrEventLogUnsupported and leaves the global logger untouched.
+func InitLogWithEventLog(logLevelStr LogLevel, appEnv Env, source string, opts ...Option) error {
+	return ErrEventLogUnsupported
+}
@@ -0,0 +1,61 @@
+//go:build windows
+
+package zerolog_wrapper
+
+import (
+	"strings"
+
+	"github.com/rs/zerolog"
+	"golang.org/x/sys/windows/svc/eventlog"
+)
+
+// eventLogID is the event ID used for every entry written to the Windows Event Log.
+const eventLogID = 1
+
+// InitLogWithEventLog initializes the global logger like InitLog, but writes
+// events to the Windows Event Log under the given source instead of stderr.
+//
+// Error, fatal and panic events become Error entries, warn events become
+// Warning entries and everything else Information entries.
+func InitLogWithEventLog(logLevelStr LogLevel, appEnv Env, source string, opts ...Option) error {
+	// registering the source needs admin rights and fails when it already
+	// exists, Open works in both cases so the error is not fatal
+	_ = eventlog.InstallAsEventCreate(source, eventlog.Error|eventlog.Warning|eventlog.Info)
+
+	el, err := eventlog.Open(source)
+	if err != nil {
+		return err
+	}
+
+	InitLog(logLevelStr, appEnv, append(opts, WithOutput(&eventLogWriter{log: el}))...)
+
+	return nil
+}
+
+// eventLogWriter routes events to the Windows Event Log based on their level.
+type eventLogWriter struct {
+	log *eventlog.Log
+}
+
+func (w *eventLogWriter) Write(p []byte) (int, error) {
+	return w.WriteLevel(zerolog.NoLevel, p)
+}
+
+func (w *eventLogWriter) WriteLevel(level zerolog.Level, p []byte) (int, error) {
+	msg := strings.TrimSuffix(string(p), "\n")
+
+	var err error
+	switch level {
+	case zerolog.ErrorLevel, zerolog.FatalLevel, zerolog.PanicLevel:
+		err = w.log.Error(eventLogID, msg)
+	case zerolog.WarnLevel:
+		err = w.log.Warning(eventLogID, msg)
+	default:
+		err = w.log.Info(eventLogID, msg)
+	}
+	if err != nil {
+		return 0, err
+	}
+
+	return len(p), nil
+}
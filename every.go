@@ -0,0 +1,44 @@
+package zerolog_wrapper
+
+import (
+	"sync"
+	"time"
+)
+
+// every calls fn every interval from a background goroutine until the
+// returned function is called, which waits for the goroutine to exit.
+// A zero or negative interval never calls fn.
+func every(interval time.Duration, fn func()) (stop func()) {
+	// time.NewTicker panics on these
+	if interval <= 0 {
+		return func() {}
+	}
+
+	done := make(chan struct{})
+	var wg sync.WaitGroup
+
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+
+		for {
+			select {
+			case <-done:
+				return
+			case <-ticker.C:
+				fn()
+			}
+		}
+	}()
+
+	var stopOnce sync.Once
+	return func() {
+		stopOnce.Do(func() {
+			close(done)
+			wg.Wait()
+		})
+	}
+}
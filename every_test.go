@@ -0,0 +1,41 @@
+package zerolog_wrapper
+
+import (
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestEvery(t *testing.T) {
+	var calls atomic.Int32
+	stop := every(time.Millisecond, func() { calls.Add(1) })
+	if !waitFor(t, func() bool { return calls.Load() >= 3 }) {
+		t.Errorf("fn called %d times, want at least 3", calls.Load())
+	}
+	stop()
+	stop()
+
+	after := calls.Load()
+	time.Sleep(10 * time.Millisecond)
+	if calls.Load() != after {
+		t.Error("fn called after stop")
+	}
+}
+
+func TestEveryNonPositiveInterval(t *testing.T) {
+	for _, interval := range []time.Duration{0, -time.Second} {
+		var calls atomic.Int32
+		stop := every(interval, func() { calls.Add(1) })
+		time.Sleep(10 * time.Millisecond)
+		stop()
+
+		if calls.Load() != 0 {
+			t.Errorf("interval %s: fn called %d times", interval, calls.Load())
+		}
+	}
+}
+
+func TestStartMemStatsReporterNonPositiveInterval(t *testing.T) {
+	useStd(t)
+	StartMemStatsReporter(0)()
+}
@@ -0,0 +1,109 @@
+package zerolog_wrapper
+
+import (
+	"errors"
+	"io"
+	"sync"
+	"time"
+
+	"github.com/rs/zerolog"
+)
+
+// errNoWriters is returned by NewFallbackWriter without writers.
+var errNoWriters = errors.New("zerolog_wrapper: a fallback writer needs at least one writer")
+
+// FallbackWriter writes to the first healthy writer of an ordered list. When
+// a write fails, or writes only part of an event, it moves down the list, eg: from a network writer to a local
+// file and then to stderr, and writes a one-time notice about the switch to
+// the writer it switched to.
+//
+// Use it with WithOutput:
+//
+//	w, err := log.NewFallbackWriter(time.Minute, networkWriter, file, os.Stderr)
+//	if err != nil {
+//		...
+//	}
+//	log.InitLog(log.InfoLevel, "prod", log.WithOutput(w))
+type FallbackWriter struct {
+	mu            sync.Mutex
+	writers       []io.Writer
+	active        int
+	retryInterval time.Duration
+	switchedAt    time.Time
+}
+
+// NewFallbackWriter returns a FallbackWriter over writers, in order of preference.
+// After switching away from the first writer it is tried again every
+// retryInterval and used again once it works; a zero retryInterval never switches back.
+// At least one writer is required.
+func NewFallbackWriter(retryInterval time.Duration, writers ...io.Writer) (*FallbackWriter, error) {
+	if len(writers) == 0 {
+		return nil, errNoWriters
+	}
+
+	return &FallbackWriter{
+		writers:       writers,
+		retryInterval: retryInterval,
+	}, nil
+}
+
+func (w *FallbackWriter) Write(p []byte) (int, error) {
+	return w.WriteLevel(zerolog.NoLevel, p)
+}
+
+func (w *FallbackWriter) WriteLevel(level zerolog.Level, p []byte) (int, error) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	if w.active > 0 && w.retryInterval > 0 && time.Since(w.switchedAt) >= w.retryInterval {
+		w.switchedAt = time.Now()
+		if n, err := writeFull(w.writers[0], level, p); err == nil {
+			w.active = 0
+			w.notice(zerolog.InfoLevel, nil, "log writer recovered, switched back to primary writer")
+			return n, nil
+		}
+	}
+
+	var failure error
+	for i := w.active; i < len(w.writers); i++ {
+		n, err := writeFull(w.writers[i], level, p)
+		if err != nil {
+			failure = err
+			continue
+		}
+		if i != w.active {
+			w.active = i
+			w.switchedAt = time.Now()
+			w.notice(zerolog.WarnLevel, failure, "log writer failed, switched to fallback writer")
+		}
+		return n, nil
+	}
+
+	return 0, failure
+}
+
+// notice writes a message about a switch to the now active writer.
+func (w *FallbackWriter) notice(level zerolog.Level, err error, msg string) {
+	l := zerolog.New(w.writers[w.active]).With().Timestamp().Logger()
+	l.WithLevel(level).Err(err).Int("writer", w.active).Msg(msg)
+}
+
+// writeFull writes p to w like writeLevel, failing with io.ErrShortWrite
+// when w writes only part of p.
+func writeFull(w io.Writer, level zerolog.Level, p []byte) (int, error) {
+	n, err := writeLevel(w, level, p)
+	if err == nil && n < len(p) {
+		err = io.ErrShortWrite
+	}
+
+	return n, err
+}
+
+// writeLevel writes p to w, passing on the level when w is a zerolog.LevelWriter.
+func writeLevel(w io.Writer, level zerolog.Level, p []byte) (int, error) {
+	if lw, ok := w.(zerolog.LevelWriter); ok {
+		return lw.WriteLevel(level, p)
+	}
+
+	return w.Write(p)
+}
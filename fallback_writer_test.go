@@ -0,0 +1,55 @@
+package zerolog_wrapper
+
+import (
+	"bytes"
+	"testing"
+	"time"
+)
+
+// shortWriter writes only half of every write without reporting an error.
+type shortWriter struct {
+	bytes.Buffer
+}
+
+func (w *shortWriter) Write(p []byte) (int, error) {
+	return w.Buffer.Write(p[:len(p)/2])
+}
+
+func TestNewFallbackWriterWithoutWriters(t *testing.T) {
+	if w, err := NewFallbackWriter(time.Minute); err == nil {
+		t.Errorf("NewFallbackWriter() = %v, want an error", w)
+	}
+}
+
+func TestFallbackWriter(t *testing.T) {
+	for name, primary := range map[string]interface {
+		Write(p []byte) (int, error)
+		String() string
+	}{
+		"failing": &failingWriter{failing: true},
+		"short":   &shortWriter{},
+	} {
+		var fallback bytes.Buffer
+		w, err := NewFallbackWriter(0, primary, &fallback)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if n, err := w.Write([]byte("event\n")); err != nil || n != len("event\n") {
+			t.Fatalf("%s: Write = %d, %v", name, n, err)
+		}
+		if !bytes.Contains(fallback.Bytes(), []byte("event\n")) || !bytes.Contains(fallback.Bytes(), []byte("switched to fallback writer")) {
+			t.Errorf("%s: event or notice missing from the fallback writer: %q", name, fallback.String())
+		}
+	}
+}
+
+func TestFallbackWriterAllFailing(t *testing.T) {
+	w, err := NewFallbackWriter(0, &shortWriter{}, &failingWriter{failing: true})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := w.Write([]byte("event\n")); err == nil {
+		t.Error("Write succeeded without a working writer")
+	}
+}
@@ -0,0 +1,88 @@
+package zerolog_wrapper
+
+import (
+	"sync"
+	"time"
+
+	"github.com/rs/zerolog"
+)
+
+var (
+	fatalExitMu sync.RWMutex
+	fatalExit   = map[Env]bool{}
+)
+
+// SetFatalExit sets whether Fatal exits the process in the given environment.
+//
+// By default it exits in every environment. After SetFatalExit(env, false) it
+// logs at error level with would_exit set to true and carries on instead, eg:
+// so a crash during development doesn't take down a whole test suite.
+//
+//	log.SetFatalExit(log.Dev, false)
+func SetFatalExit(appEnv Env, exit bool) {
+	fatalExitMu.Lock()
+	defer fatalExitMu.Unlock()
+
+	fatalExit[appEnv] = exit
+}
+
+// fatalExits reports whether Fatal exits the process in appEnv.
+func fatalExits(appEnv Env) bool {
+	fatalExitMu.RLock()
+	defer fatalExitMu.RUnlock()
+
+	exit, ok := fatalExit[appEnv]
+	return exit || !ok
+}
+
+// defaultFatalFlushTimeout bounds the flush of the buffering writers before a fatal event exits.
+const defaultFatalFlushTimeout = 5 * time.Second
+
+// WithFatalFlushTimeout sets how long a fatal event waits for the buffering
+// writers (WithBuffer, WithAsync, WithGzip, outputs like stream.Writer and
+// the like) to write out what they hold before the process exits, 5 seconds by default. The fatal event
+// itself and everything logged before it is flushed, unless the output is
+// stuck for longer than timeout, in which case the process exits regardless.
+// A zero timeout exits without waiting.
+func WithFatalFlushTimeout(timeout time.Duration) Option {
+	return func(o *options) {
+		o.fatalFlushTimeout = timeout
+	}
+}
+
+// fatalFlushWriter flushes every buffering writer of lifecycle after writing a fatal event,
+// which zerolog follows with os.Exit.
+type fatalFlushWriter struct {
+	out       zerolog.LevelWriter
+	lifecycle *lifecycle
+	timeout   time.Duration
+}
+
+func (w fatalFlushWriter) Write(p []byte) (int, error) {
+	return w.WriteLevel(zerolog.NoLevel, p)
+}
+
+func (w fatalFlushWriter) WriteLevel(level zerolog.Level, p []byte) (int, error) {
+	n, err := w.out.WriteLevel(level, p)
+	if level != zerolog.FatalLevel {
+		return n, err
+	}
+
+	done := make(chan error, 1)
+	go func() {
+		done <- w.lifecycle.flush()
+	}()
+
+	timer := time.NewTimer(w.timeout)
+	defer timer.Stop()
+
+	select {
+	case flushErr := <-done:
+		if err == nil {
+			err = flushErr
+		}
+	case <-timer.C:
+	}
+
+	return n, err
+}
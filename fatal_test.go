@@ -0,0 +1,118 @@
+package zerolog_wrapper
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/ashokrajar/zerolog_wrapper/stream"
+)
+
+const (
+	fatalFileEnv   = "ZEROLOG_WRAPPER_FATAL_FILE"
+	fatalWriterEnv = "ZEROLOG_WRAPPER_FATAL_WRITER"
+)
+
+// fatalWriters returns the options writing to the file at path through each buffering writer.
+var fatalWriters = map[string]func(path string) []Option{
+	"buffer": func(path string) []Option { return []Option{WithFile(path), WithBuffer(64*1024, time.Hour)} },
+	"async":  func(path string) []Option { return []Option{WithFile(path), WithAsync(1024)} },
+	"stream": func(path string) []Option {
+		return []Option{WithOutput(stream.NewWriter(filePublisher(path), stream.WithFlushInterval(0)))}
+	},
+}
+
+// filePublisher appends the published events to the file at its path.
+type filePublisher string
+
+func (p filePublisher) Publish(ctx context.Context, batch []stream.Message) error {
+	f, err := os.OpenFile(string(p), os.O_CREATE|os.O_APPEND|os.O_WRONLY, 0o644)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+
+	for _, m := range batch {
+		if _, err := f.Write(m.Value); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// TestFatalFlushesBufferingWriters logs a fatal event in a subprocess, which
+// exits, and checks that neither it nor the events before it were lost.
+func TestFatalFlushesBufferingWriters(t *testing.T) {
+	if path := os.Getenv(fatalFileEnv); path != "" {
+		opts := append([]Option{DisableHostIP()}, fatalWriters[os.Getenv(fatalWriterEnv)](path)...)
+		l, err := New(InfoLevel, Prod, opts...)
+		if err != nil {
+			t.Fatal(err)
+		}
+		l.Info().Msg("first")
+		l.Info().Msg("second")
+		l.Fatal().Msg("fatal")
+		t.Fatal("Fatal did not exit")
+	}
+
+	for name := range fatalWriters {
+		t.Run(name, func(t *testing.T) {
+			path := filepath.Join(t.TempDir(), "app.log")
+			cmd := exec.Command(os.Args[0], "-test.run=^TestFatalFlushesBufferingWriters$")
+			cmd.Env = append(os.Environ(), fatalFileEnv+"="+path, fatalWriterEnv+"="+name)
+			out, err := cmd.CombinedOutput()
+
+			var exitErr *exec.ExitError
+			if !errors.As(err, &exitErr) || exitErr.ExitCode() != 1 {
+				t.Fatalf("subprocess: %v, want exit status 1\n%s", err, out)
+			}
+
+			logged, err := os.ReadFile(path)
+			if err != nil {
+				t.Fatal(err)
+			}
+			for _, message := range []string{"first", "second", "fatal"} {
+				if !bytes.Contains(logged, []byte(`"message":"`+message+`"`)) {
+					t.Errorf("%q missing after exit:\n%s", message, logged)
+				}
+			}
+			if !bytes.Contains(logged, []byte(`"level":"fatal"`)) {
+				t.Errorf("fatal level missing after exit:\n%s", logged)
+			}
+		})
+	}
+}
+
+func TestFatalExitsByDefault(t *testing.T) {
+	for _, env := range []Env{Prod, Stage, QA, Dev, "unknown"} {
+		if !fatalExits(env) {
+			t.Errorf("Fatal doesn't exit in %s by default", env)
+		}
+	}
+}
+
+func TestSetFatalExit(t *testing.T) {
+	SetFatalExit(Dev, false)
+	t.Cleanup(func() {
+		fatalExitMu.Lock()
+		defer fatalExitMu.Unlock()
+		delete(fatalExit, Dev)
+	})
+
+	var buf bytes.Buffer
+	l, err := New(InfoLevel, Dev, DisableHostIP(), WithOutput(&buf))
+	if err != nil {
+		t.Fatal(err)
+	}
+	l.Fatal().Msg("carry on")
+
+	if !bytes.Contains(buf.Bytes(), []byte(`"level":"error"`)) || !bytes.Contains(buf.Bytes(), []byte(`"would_exit":true`)) {
+		t.Errorf("got %s, want an error event with would_exit", buf.String())
+	}
+}
@@ -0,0 +1,36 @@
+package zerolog_wrapper
+
+import "github.com/rs/zerolog"
+
+// WithCanonicalFieldOrder makes every JSON event start with the time, level
+// and message fields, followed by all other fields in the order they were added.
+//
+// Without this option zerolog writes the level first, then the context fields,
+// the event fields, the hook fields (time, caller and host_ip) and the message last.
+func WithCanonicalFieldOrder() Option {
+	return func(o *options) {
+		o.transforms = append(o.transforms, canonicalFieldOrder)
+	}
+}
+
+// canonicalFieldOrder moves the time, level and message fields to the front.
+func canonicalFieldOrder(fields []jsonField) []jsonField {
+	leading := []string{zerolog.TimestampFieldName, zerolog.LevelFieldName, zerolog.MessageFieldName}
+
+	ordered := make([]jsonField, 0, len(fields))
+	for _, key := range leading {
+		for _, field := range fields {
+			if field.key == key {
+				ordered = append(ordered, field)
+				break
+			}
+		}
+	}
+	for _, field := range fields {
+		if field.key != zerolog.TimestampFieldName && field.key != zerolog.LevelFieldName && field.key != zerolog.MessageFieldName {
+			ordered = append(ordered, field)
+		}
+	}
+
+	return ordered
+}
@@ -0,0 +1,52 @@
+package zerolog_wrapper
+
+import (
+	"bytes"
+	"regexp"
+	"testing"
+)
+
+var fieldKeyPattern = regexp.MustCompile(`"([a-z_]+)":`)
+
+func fieldKeys(line []byte) []string {
+	var keys []string
+	for _, match := range fieldKeyPattern.FindAllSubmatch(line, -1) {
+		keys = append(keys, string(match[1]))
+	}
+	return keys
+}
+
+func TestFieldOrder(t *testing.T) {
+	tests := []struct {
+		name string
+		opts []Option
+		want []string
+	}{
+		{"default", nil, []string{"level", "svc", "a", "time", "caller", "message"}},
+		{"canonical", []Option{WithCanonicalFieldOrder()}, []string{"time", "level", "message", "svc", "a", "caller"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var buf bytes.Buffer
+			opts := append([]Option{DisableHostIP(), WithOutput(&buf), WithMinCallerLevel(InfoLevel)}, tt.opts...)
+			l, err := New(InfoLevel, Prod, opts...)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			logger := l.GetLogger().With().Str("svc", "api").Logger()
+			logger.Info().Str("a", "b").Msg("hello")
+
+			got := fieldKeys(buf.Bytes())
+			if len(got) != len(tt.want) {
+				t.Fatalf("keys = %v, want %v", got, tt.want)
+			}
+			for n := range got {
+				if got[n] != tt.want[n] {
+					t.Fatalf("keys = %v, want %v", got, tt.want)
+				}
+			}
+		})
+	}
+}
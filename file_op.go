@@ -0,0 +1,84 @@
+package zerolog_wrapper
+
+import (
+	"os"
+	"os/user"
+	"path/filepath"
+	"regexp"
+	"strings"
+	"sync"
+)
+
+// File operations logged by LogFileOp.
+const (
+	FileOpRead   = "read"
+	FileOpWrite  = "write"
+	FileOpDelete = "delete"
+)
+
+var (
+	homeDirOnce sync.Once
+	homeDir     string
+	userName    string
+)
+
+// userDirPattern matches the home directory of any user on Linux and macOS.
+var userDirPattern = regexp.MustCompile(`^/(home|Users)/[^/]+`)
+
+// LogFileOp logs a file operation, eg: FileOpRead, with its op, path, bytes
+// and outcome. A nil err is a success logged at debug level, anything else
+// a failure logged at error level carrying err.
+//
+// The home directory in path is replaced with "~", the home directories of
+// other users and the name of the current user with "[REDACTED]", so paths
+// don't reveal who ran the program.
+//
+// eg:
+//
+//	n, err := f.Write(data)
+//	log.LogFileOp(log.FileOpWrite, f.Name(), int64(n), err)
+//	// Output: {"level":"debug","op":"write","path":"~/reports/2024-05.csv","bytes":5120,"outcome":"success"}
+func LogFileOp(op, path string, size int64, err error) {
+	e := Debug()
+	outcome := "success"
+	if err != nil {
+		e = Error().Func(Err(err))
+		outcome = "failure"
+	}
+
+	e.Str("op", op).
+		Str("path", redactPath(path)).
+		Int64("bytes", size).
+		Str("outcome", outcome).
+		Msg("file operation")
+}
+
+// redactPath masks the home directories and the user name in path.
+func redactPath(path string) string {
+	homeDirOnce.Do(func() {
+		homeDir, _ = os.UserHomeDir()
+		if u, err := user.Current(); err == nil {
+			userName = u.Username
+		}
+	})
+
+	path = filepath.Clean(path)
+	switch {
+	case homeDir != "" && (path == homeDir || strings.HasPrefix(path, homeDir+string(filepath.Separator))):
+		path = "~" + path[len(homeDir):]
+	default:
+		path = userDirPattern.ReplaceAllString(path, "/$1/"+redactedValue)
+	}
+
+	if userName != "" {
+		segments := strings.Split(path, string(filepath.Separator))
+		for i, segment := range segments {
+			if segment == userName {
+				segments[i] = redactedValue
+			}
+		}
+		path = strings.Join(segments, string(filepath.Separator))
+	}
+
+	return path
+}
@@ -0,0 +1,61 @@
+package zerolog_wrapper
+
+import (
+	"context"
+	"fmt"
+	"os"
+)
+
+// WithFile writes events to the file at path, which is created when missing
+// and appended to otherwise, instead of the default destination. The file is
+// opened for writing when the logger is set up, so a missing directory or a
+// lack of permissions is reported right away: by InitLogE and New as an
+// error, by InitLog on stderr before carrying on without it.
+// Shutdown closes the file. It takes precedence over WithOutput.
+func WithFile(path string) Option {
+	return func(o *options) {
+		o.filePath = path
+	}
+}
+
+// openFile opens the file of WithFile as the output of o.
+func (o *options) openFile() error {
+	if o.filePath == "" {
+		return nil
+	}
+
+	f, err := os.OpenFile(o.filePath, os.O_WRONLY|os.O_APPEND|os.O_CREATE, 0o644)
+	if err != nil {
+		return fmt.Errorf("log file not writable: %w", err)
+	}
+	o.file = f
+	o.output = f
+
+	return nil
+}
+
+// checkFile reports whether the file at path can be opened by openFile.
+func checkFile(path string) error {
+	o := &options{filePath: path}
+	if err := o.openFile(); err != nil {
+		return err
+	}
+
+	return o.file.Close()
+}
+
+// InitLogE initializes the global logger like InitLog, but returns an error
+// instead of ignoring options which can't be applied, eg: a WithFile path
+// which isn't writable, so a program can fail fast instead of losing its
+// logs. The global logger is left untouched on error and InitLogE
+// can be called again, once it is set up later calls do nothing.
+//
+// eg:
+//
+//	if err := log.InitLogE(log.InfoLevel, "prod", log.WithFile("/var/log/app/app.log")); err != nil {
+//		fmt.Fprintln(os.Stderr, err)
+//		os.Exit(1)
+//	}
+func InitLogE(logLevelStr LogLevel, appEnv Env, opts ...Option) error {
+	return initStd(context.Background(), logLevelStr, appEnv, newOptions(opts))
+}
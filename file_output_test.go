@@ -0,0 +1,36 @@
+package zerolog_wrapper
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestWithFileNotWritable(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "missing", "app.log")
+	if _, err := New(InfoLevel, Prod, WithFile(path)); err == nil {
+		t.Fatal("New succeeded with a log file in a missing directory")
+	}
+}
+
+func TestWithFile(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "app.log")
+	l, err := New(InfoLevel, Prod, DisableHostIP(), WithFile(path))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	l.Info().Msg("to file")
+	if err := l.Shutdown(); err != nil {
+		t.Fatal(err)
+	}
+
+	b, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(string(b), `"message":"to file"`) {
+		t.Errorf("event missing from the log file:\n%s", b)
+	}
+}
@@ -0,0 +1,48 @@
+package zerolog_wrapper
+
+import (
+	"sync"
+	"sync/atomic"
+
+	"github.com/rs/zerolog"
+)
+
+var (
+	filtersMu sync.Mutex
+	// filters holds the []func(zerolog.Level, string) bool registered by RegisterFilter
+	filters atomic.Value
+)
+
+// RegisterFilter adds keep to the filters every event has to pass. An event
+// for which any filter returns false is dropped, eg: the access logs of
+// health checks. Filters run synchronously for every enabled event, so keep
+// them cheap.
+//
+// eg:
+//
+//	log.RegisterFilter(func(level zerolog.Level, msg string) bool {
+//		return msg != "health check"
+//	})
+func RegisterFilter(keep func(level zerolog.Level, msg string) bool) {
+	filtersMu.Lock()
+	defer filtersMu.Unlock()
+
+	current, _ := filters.Load().([]func(zerolog.Level, string) bool)
+	filters.Store(append(append([]func(zerolog.Level, string) bool(nil), current...), keep))
+}
+
+// filterHook drops the events rejected by a filter registered through RegisterFilter.
+type filterHook struct{}
+
+func (filterHook) Run(e *zerolog.Event, level zerolog.Level, msg string) {
+	if isContextRender(msg) {
+		return
+	}
+	fs, _ := filters.Load().([]func(zerolog.Level, string) bool)
+	for _, keep := range fs {
+		if !keep(level, msg) {
+			e.Discard()
+			return
+		}
+	}
+}
@@ -0,0 +1,38 @@
+package zerolog_wrapper
+
+import (
+	"context"
+	"sort"
+
+	"github.com/rs/zerolog"
+)
+
+// WithFlags returns a copy of ctx whose logger carries the active feature flag
+// variants as a nested "flags" object. Every event logged through
+// FromContext(ctx) then shows which variants were in play.
+//
+// eg:
+//
+//	ctx = log.WithFlags(ctx, map[string]string{"new_checkout": "treatment"})
+//	log.FromContext(ctx).Info().Msg("order placed")
+//	// Output: {"level":"info","flags":{"new_checkout":"treatment"},"message":"order placed"}
+func WithFlags(ctx context.Context, flags map[string]string) context.Context {
+	l := contextLogger(ctx)
+	return WithContext(ctx, l.With().Dict("flags", stringDict(flags)).Logger())
+}
+
+// stringDict returns m as a dict with its keys in sorted order.
+func stringDict(m map[string]string) *zerolog.Event {
+	keys := make([]string, 0, len(m))
+	for key := range m {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	dict := zerolog.Dict()
+	for _, key := range keys {
+		dict = dict.Str(key, m[key])
+	}
+
+	return dict
+}
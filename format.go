@@ -0,0 +1,163 @@
+package zerolog_wrapper
+
+import (
+	"encoding/json"
+	"io"
+	"sync"
+	"time"
+
+	"github.com/rs/zerolog"
+)
+
+type Format string
+
+const (
+	JSONFormat    Format = "json"
+	ConsoleFormat Format = "console"
+	ECSFormat     Format = "ecs"
+	GCPFormat     Format = "gcp"
+)
+
+// SetFormat switches the output of the global logger between JSON, the
+// human friendly console format and the ECS and GCP field layouts at
+// runtime, eg: while attaching an interactive debug session to a running
+// service. Level and context fields are kept as they are.
+//
+// ECSFormat names the standard fields after the Elastic Common Schema, eg:
+// @timestamp and log.level. GCPFormat writes the level as the severity field
+// of Google Cloud Logging.
+func SetFormat(format Format) {
+	std.SetFormat(format)
+}
+
+// SetFormat switches the output format of the instance at runtime, see SetFormat.
+// On a Clone it has no effect until SetOutput gave the copy its own output.
+func (i *Instance) SetFormat(format Format) {
+	if !i.mutable("SetFormat") {
+		return
+	}
+
+	i.mu.RLock()
+	w := i.format
+	i.mu.RUnlock()
+	if w != nil {
+		w.setFormat(format)
+	}
+}
+
+// formatWriter writes events to out either as they are or through a console writer.
+type formatWriter struct {
+	mu      sync.RWMutex
+	out     io.Writer
+	console io.Writer
+	format  Format
+}
+
+func newFormatWriter(out io.Writer, format Format) *formatWriter {
+	return &formatWriter{
+		out: out,
+		console: zerolog.ConsoleWriter{
+			Out:        out,
+			TimeFormat: time.RFC3339,
+		},
+		format: format,
+	}
+}
+
+func (w *formatWriter) setFormat(format Format) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	w.format = format
+}
+
+func (w *formatWriter) Write(p []byte) (int, error) {
+	return w.WriteLevel(zerolog.NoLevel, p)
+}
+
+func (w *formatWriter) WriteLevel(level zerolog.Level, p []byte) (int, error) {
+	w.mu.RLock()
+	defer w.mu.RUnlock()
+
+	var mapFields fieldTransform
+	switch w.format {
+	case ConsoleFormat:
+		return w.console.Write(p)
+	case ECSFormat:
+		mapFields = ecsFields
+	case GCPFormat:
+		mapFields = gcpFields
+	default:
+		return writeLevel(w.out, level, p)
+	}
+
+	fields, err := decodeFields(p)
+	if err != nil {
+		return writeLevel(w.out, level, p)
+	}
+	if _, err := writeLevel(w.out, level, encodeFields(mapFields(fields))); err != nil {
+		return 0, err
+	}
+
+	// report the original length, zerolog treats anything else as a short write
+	return len(p), nil
+}
+
+// ecsVersion is the version of the Elastic Common Schema written by ECSFormat.
+const ecsVersion = "1.6.0"
+
+// ecsFields renames the standard fields after the Elastic Common Schema.
+func ecsFields(fields []jsonField) []jsonField {
+	names := map[string]string{
+		zerolog.TimestampFieldName:  "@timestamp",
+		zerolog.LevelFieldName:      "log.level",
+		zerolog.ErrorFieldName:      "error.message",
+		zerolog.CallerFieldName:     "log.origin.file.name",
+		zerolog.ErrorStackFieldName: "error.stack_trace",
+	}
+	for i, field := range fields {
+		if name, ok := names[field.key]; ok {
+			fields[i].key = name
+		}
+	}
+
+	return append(fields, jsonField{key: "ecs.version", value: []byte(`"` + ecsVersion + `"`)})
+}
+
+// gcpSeverities maps level names onto the severities of Google Cloud Logging.
+var gcpSeverities = map[string]string{
+	zerolog.LevelTraceValue: "DEBUG",
+	zerolog.LevelDebugValue: "DEBUG",
+	zerolog.LevelInfoValue:  "INFO",
+	zerolog.LevelWarnValue:  "WARNING",
+	zerolog.LevelErrorValue: "ERROR",
+	zerolog.LevelFatalValue: "CRITICAL",
+	zerolog.LevelPanicValue: "ALERT",
+}
+
+// gcpFields writes the level as the severity field understood by Google Cloud Logging.
+func gcpFields(fields []jsonField) []jsonField {
+	for i, field := range fields {
+		if field.key != zerolog.LevelFieldName {
+			continue
+		}
+		fields[i].key = "severity"
+		var level string
+		if err := json.Unmarshal(field.value, &level); err == nil {
+			if severity, ok := gcpSeverities[level]; ok {
+				fields[i].value = []byte(`"` + severity + `"`)
+			}
+		}
+	}
+
+	return fields
+}
+
+// Flush flushes the underlying writer when it buffers.
+func (w *formatWriter) Flush() error {
+	if f, ok := w.out.(flusher); ok {
+		return f.Flush()
+	}
+
+	return nil
+}
@@ -2,10 +2,14 @@ module github.com/ashokrajar/zerolog_wrapper
 
 go 1.20
 
-require github.com/rs/zerolog v1.29.1
+require (
+	github.com/go-logr/logr v1.2.4
+	github.com/rs/zerolog v1.29.1
+	golang.org/x/sys v0.0.0-20210927094055-39ccf1dd6fa6
+	google.golang.org/protobuf v1.33.0
+)
 
 require (
 	github.com/mattn/go-colorable v0.1.12 // indirect
 	github.com/mattn/go-isatty v0.0.14 // indirect
-	golang.org/x/sys v0.0.0-20210927094055-39ccf1dd6fa6 // indirect
 )
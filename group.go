@@ -0,0 +1,26 @@
+package zerolog_wrapper
+
+import "github.com/rs/zerolog"
+
+// Group nests the fields added by fields under name, eg: all HTTP fields
+// under http and all database fields under db, matching the nested layout of
+// ECS and keeping large events organized. Groups can be nested in groups.
+//
+// Use it with the Func method of an event:
+//
+//	log.Info().
+//		Func(log.Group("http", func(g *zerolog.Event) {
+//			g.Str("method", r.Method).Int("status", status)
+//		})).
+//		Func(log.Group("db", func(g *zerolog.Event) {
+//			g.Int("queries", queries)
+//		})).
+//		Msg("request")
+//	// Output: {"level":"info","http":{"method":"GET","status":200},"db":{"queries":3},"message":"request"}
+func Group(name string, fields func(g *zerolog.Event)) func(e *zerolog.Event) {
+	return func(e *zerolog.Event) {
+		g := zerolog.Dict()
+		fields(g)
+		e.Dict(name, g)
+	}
+}
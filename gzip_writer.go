@@ -0,0 +1,76 @@
+package zerolog_wrapper
+
+import (
+	"compress/gzip"
+	"io"
+	"sync"
+)
+
+// WithGzip compresses the output with gzip as it is written, eg: to save disk
+// space on a high volume log file. Flush writes out everything compressed so
+// far, Shutdown finalizes the gzip stream. Events logged after Shutdown start
+// a new gzip member, which gzip readers decompress as part of the same file.
+//
+// The tradeoff is that the active file can't be followed with tail: until
+// Flush or Shutdown the most recent events stay in the compressor, and even
+// flushed output needs eg: zcat to read.
+func WithGzip(level int) Option {
+	return func(o *options) {
+		o.gzipLevel = &level
+	}
+}
+
+// gzipWriter compresses everything written to it into out.
+type gzipWriter struct {
+	mu     sync.Mutex
+	out    io.Writer
+	gz     *gzip.Writer
+	closed bool
+}
+
+func newGzipWriter(out io.Writer, level int) (*gzipWriter, error) {
+	gz, err := gzip.NewWriterLevel(out, level)
+	if err != nil {
+		return nil, err
+	}
+
+	return &gzipWriter{out: out, gz: gz}, nil
+}
+
+func (w *gzipWriter) Write(p []byte) (int, error) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	if w.closed {
+		w.gz.Reset(w.out)
+		w.closed = false
+	}
+
+	return w.gz.Write(p)
+}
+
+// flush writes out the data compressed so far. It is deliberately not named
+// Flush, writers wrapping out flush it after every event, which would defeat compression.
+func (w *gzipWriter) flush() error {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	if w.closed {
+		return nil
+	}
+
+	return w.gz.Flush()
+}
+
+// Close finalizes the gzip stream.
+func (w *gzipWriter) Close() error {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	if w.closed {
+		return nil
+	}
+	w.closed = true
+
+	return w.gz.Close()
+}
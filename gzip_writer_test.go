@@ -0,0 +1,44 @@
+package zerolog_wrapper
+
+import (
+	"compress/gzip"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestGzipFileRoundTrip(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "app.log.gz")
+	l, err := New(InfoLevel, Prod, DisableHostIP(), WithFile(path), WithGzip(gzip.DefaultCompression))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	l.Info().Msg("first")
+	l.Warn().Msg("second")
+	if err := l.Shutdown(); err != nil {
+		t.Fatal(err)
+	}
+
+	f, err := os.Open(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer f.Close()
+
+	r, err := gzip.NewReader(f)
+	if err != nil {
+		t.Fatal(err)
+	}
+	b, err := io.ReadAll(r)
+	if err != nil {
+		t.Fatalf("gzip stream not finalized: %v", err)
+	}
+
+	lines := strings.Split(strings.TrimSpace(string(b)), "\n")
+	if len(lines) != 2 || !strings.Contains(lines[0], `"first"`) || !strings.Contains(lines[1], `"second"`) {
+		t.Errorf("unexpected content:\n%s", b)
+	}
+}
@@ -0,0 +1,42 @@
+package zerolog_wrapper
+
+import (
+	"net"
+	"sync"
+
+	"github.com/rs/zerolog"
+)
+
+var (
+	localIPOnce sync.Once
+	localIP     net.IP
+)
+
+// DisableHostIP leaves the host_ip field out of every event, so the logger
+// never looks up the local address.
+func DisableHostIP() Option {
+	return func(o *options) {
+		o.disableHostIP = true
+	}
+}
+
+// lazyLocalIP returns the local address, looked up on the first call only.
+func lazyLocalIP() net.IP {
+	localIPOnce.Do(func() {
+		localIP = getLocalIP()
+	})
+
+	return localIP
+}
+
+// hostIPHook adds the host_ip field, so the local address is only looked up once an event is logged.
+type hostIPHook struct{}
+
+func (hostIPHook) Run(e *zerolog.Event, level zerolog.Level, msg string) {
+	if isContextRender(msg) {
+		return
+	}
+	if ip := lazyLocalIP(); ip != nil {
+		e.IPAddr("host_ip", ip)
+	}
+}
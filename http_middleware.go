@@ -0,0 +1,308 @@
+package zerolog_wrapper
+
+import (
+	"context"
+	"crypto/tls"
+	"fmt"
+	"net/http"
+	"runtime/debug"
+	"strings"
+	"time"
+
+	"github.com/rs/zerolog"
+)
+
+// LevelOverrideMiddleware returns a middleware which asks lookup for a log
+// level for each request and, when it returns one, overrides the level of
+// the request context (see ContextWithLevel). Handlers logging through
+// FromContext(r.Context()) then log at that level.
+//
+// eg: enable debug logging for a single tenant
+//
+//	mw := log.LevelOverrideMiddleware(func(r *http.Request) (log.LogLevel, bool) {
+//		return log.DebugLevel, r.Header.Get("X-Tenant-ID") == "tenant-x"
+//	})
+//	http.ListenAndServe(":8080", mw(handler))
+func LevelOverrideMiddleware(lookup func(r *http.Request) (LogLevel, bool)) func(next http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if level, ok := lookup(r); ok {
+				r = r.WithContext(ContextWithLevel(r.Context(), level))
+			}
+			next.ServeHTTP(w, r)
+		})
+	}
+}
+
+// LevelFromHeader returns a lookup for LevelOverrideMiddleware which reads the
+// level from the named request header. Unknown level names are ignored.
+//
+// Only use it behind a trusted proxy, otherwise any client can turn on verbose logging.
+func LevelFromHeader(name string) func(r *http.Request) (LogLevel, bool) {
+	return func(r *http.Request) (LogLevel, bool) {
+		return parseLevel(r.Header.Get(name))
+	}
+}
+
+// MiddlewareOption configures the middleware returned by Middleware.
+type MiddlewareOption func(*middlewareOptions)
+
+type middlewareOptions struct {
+	recoverPanics         bool
+	responseCorrelationID bool
+	requestHeaders        []string
+	responseHeaders       []string
+	accessLogSampler      *KeyedSampler
+	tlsFields             bool
+}
+
+// WithoutRecovery lets panics of the handler propagate instead of being
+// recovered, logged and answered with a 500 response.
+func WithoutRecovery() MiddlewareOption {
+	return func(o *middlewareOptions) {
+		o.recoverPanics = false
+	}
+}
+
+// WithResponseCorrelationID sends the correlation ID back to the client in the CorrelationIDHeader response header.
+func WithResponseCorrelationID() MiddlewareOption {
+	return func(o *middlewareOptions) {
+		o.responseCorrelationID = true
+	}
+}
+
+// WithAccessLogSampling samples the access log entries of successful and
+// client error requests by their path through sampler. Requests logged at
+// error level, by default those answered with a 5xx status, are always
+// logged. Sampled out entries are counted in Stats.
+func WithAccessLogSampling(sampler *KeyedSampler) MiddlewareOption {
+	return func(o *middlewareOptions) {
+		o.accessLogSampler = sampler
+	}
+}
+
+// WithTLSFields logs the negotiated TLS version, the cipher suite and, for
+// mutual TLS, the subject of the client certificate under tls.
+// Requests which didn't come in over TLS get no tls field.
+func WithTLSFields() MiddlewareOption {
+	return func(o *middlewareOptions) {
+		o.tlsFields = true
+	}
+}
+
+// tlsVersionNames names the TLS versions, tls.VersionName needs Go 1.21.
+var tlsVersionNames = map[uint16]string{
+	tls.VersionTLS10: "TLS 1.0",
+	tls.VersionTLS11: "TLS 1.1",
+	tls.VersionTLS12: "TLS 1.2",
+	tls.VersionTLS13: "TLS 1.3",
+}
+
+// tlsDict returns the details of the TLS connection state, or nil without TLS.
+func tlsDict(state *tls.ConnectionState) *zerolog.Event {
+	if state == nil {
+		return nil
+	}
+
+	version, ok := tlsVersionNames[state.Version]
+	if !ok {
+		version = fmt.Sprintf("0x%04X", state.Version)
+	}
+
+	dict := zerolog.Dict().
+		Str("version", version).
+		Str("cipher_suite", tls.CipherSuiteName(state.CipherSuite))
+	if len(state.PeerCertificates) > 0 {
+		dict = dict.Str("client_subject", state.PeerCertificates[0].Subject.String())
+	}
+
+	return dict
+}
+
+// sensitiveHeaders are always redacted, even when allowlisted.
+var sensitiveHeaders = map[string]bool{
+	"Authorization":       true,
+	"Proxy-Authorization": true,
+	"Cookie":              true,
+	"Set-Cookie":          true,
+}
+
+// WithRequestHeaders logs the named request headers, and only those, under
+// request_headers. Credentials such as Authorization and Cookie are
+// logged as "[REDACTED]" even when named.
+//
+// eg:
+//
+//	log.Middleware(log.WithRequestHeaders("User-Agent", "X-Forwarded-For"))
+func WithRequestHeaders(names ...string) MiddlewareOption {
+	return func(o *middlewareOptions) {
+		o.requestHeaders = append(o.requestHeaders, names...)
+	}
+}
+
+// WithResponseHeaders logs the named response headers, and only those, under
+// response_headers, redacted like WithRequestHeaders.
+func WithResponseHeaders(names ...string) MiddlewareOption {
+	return func(o *middlewareOptions) {
+		o.responseHeaders = append(o.responseHeaders, names...)
+	}
+}
+
+// headerDict returns the allowlisted headers present in h, or nil if there are none.
+func headerDict(h http.Header, names []string) *zerolog.Event {
+	var dict *zerolog.Event
+	for _, name := range names {
+		name = http.CanonicalHeaderKey(name)
+		values := h.Values(name)
+		if len(values) == 0 {
+			continue
+		}
+		if dict == nil {
+			dict = zerolog.Dict()
+		}
+		if sensitiveHeaders[name] {
+			dict = dict.Str(name, redactedValue)
+			continue
+		}
+		dict = dict.Str(name, strings.Join(values, ", "))
+	}
+
+	return dict
+}
+
+// Middleware returns an HTTP middleware which logs every request with its
+// method, path, status, response size and duration, at the level
+// LevelForStatus returns for the status.
+//
+// Each request gets a correlation ID, taken from the CorrelationIDHeader
+// request header or freshly generated, which is stored in the request context
+// (see CorrelationIDFromContext) and carried by the context logger, so
+// handlers logging through FromContext(r.Context()) are correlated with the
+// access log.
+//
+// By default a panicking handler is recovered, logged at error level with its
+// stack and answered with a 500 response instead of dropping the connection.
+//
+// eg:
+//
+//	http.ListenAndServe(":8080", log.Middleware()(handler))
+func Middleware(opts ...MiddlewareOption) func(next http.Handler) http.Handler {
+	o := &middlewareOptions{recoverPanics: true}
+	for _, opt := range opts {
+		opt(o)
+	}
+
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			start := time.Now()
+
+			correlationID := r.Header.Get(CorrelationIDHeader)
+			if correlationID == "" {
+				correlationID = newID()
+			}
+			if o.responseCorrelationID {
+				w.Header().Set(CorrelationIDHeader, correlationID)
+			}
+
+			ctx := WithCorrelationID(r.Context(), correlationID)
+			l := contextLogger(ctx)
+			ctx = WithContext(ctx, l.With().Str("correlation_id", correlationID).Logger())
+			r = r.WithContext(ctx)
+
+			rec := &responseRecorder{ResponseWriter: w}
+
+			if o.recoverPanics {
+				defer func() {
+					p := recover()
+					if p == nil {
+						return
+					}
+					if p == http.ErrAbortHandler {
+						// the handler deliberately aborted the response
+						panic(p)
+					}
+
+					panicFields(FromContext(ctx).Error(), p, debug.Stack()).
+						Str("method", r.Method).
+						Str("path", r.URL.Path).
+						Msg("recovered from panic in HTTP handler")
+					if !rec.wroteHeader {
+						http.Error(rec, http.StatusText(http.StatusInternalServerError), http.StatusInternalServerError)
+					}
+					logRequest(ctx, o, r, rec, start)
+				}()
+			}
+
+			next.ServeHTTP(rec, r)
+			logRequest(ctx, o, r, rec, start)
+		})
+	}
+}
+
+// logRequest writes the access log entry of a finished request.
+func logRequest(ctx context.Context, o *middlewareOptions, r *http.Request, rec *responseRecorder, start time.Time) {
+	status := rec.status
+	if !rec.wroteHeader {
+		status = http.StatusOK
+	}
+
+	level := toZerologLevel(LevelForStatus(status))
+
+	if o.accessLogSampler != nil && level < zerolog.ErrorLevel && !o.accessLogSampler.Sample(r.URL.Path) {
+		std.counters.countSampledOut(level)
+		return
+	}
+
+	l := FromContext(ctx)
+	e := l.WithLevel(level)
+
+	if dict := headerDict(r.Header, o.requestHeaders); dict != nil {
+		e = e.Dict("request_headers", dict)
+	}
+	if dict := headerDict(rec.Header(), o.responseHeaders); dict != nil {
+		e = e.Dict("response_headers", dict)
+	}
+	if o.tlsFields {
+		if dict := tlsDict(r.TLS); dict != nil {
+			e = e.Dict("tls", dict)
+		}
+	}
+
+	e.Str("method", r.Method).
+		Str("path", r.URL.Path).
+		Int("status", status).
+		Int("bytes", rec.bytes).
+		Dur("duration", time.Since(start)).
+		Msg("request")
+}
+
+// responseRecorder captures the status and size of a response.
+type responseRecorder struct {
+	http.ResponseWriter
+	status      int
+	bytes       int
+	wroteHeader bool
+}
+
+func (rec *responseRecorder) WriteHeader(status int) {
+	if !rec.wroteHeader {
+		rec.status = status
+		rec.wroteHeader = true
+	}
+	rec.ResponseWriter.WriteHeader(status)
+}
+
+func (rec *responseRecorder) Write(p []byte) (int, error) {
+	if !rec.wroteHeader {
+		rec.WriteHeader(http.StatusOK)
+	}
+	n, err := rec.ResponseWriter.Write(p)
+	rec.bytes += n
+
+	return n, err
+}
+
+// Unwrap gives http.ResponseController access to the wrapped ResponseWriter.
+func (rec *responseRecorder) Unwrap() http.ResponseWriter {
+	return rec.ResponseWriter
+}
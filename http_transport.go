@@ -0,0 +1,65 @@
+package zerolog_wrapper
+
+import (
+	"net/http"
+	"time"
+
+	"github.com/rs/zerolog"
+)
+
+type loggingTransport struct {
+	base http.RoundTripper
+}
+
+// LoggingTransport wraps base so that every outbound request is logged with
+// its method, URL, status and duration. The correlation ID found in the
+// request context is sent along in the CorrelationIDHeader header.
+//
+// Transport failures are logged at error level and responses at the level
+// LevelForStatus returns for their status.
+// A nil base uses http.DefaultTransport.
+//
+// eg:
+//
+//	client := &http.Client{Transport: log.LoggingTransport(nil)}
+func LoggingTransport(base http.RoundTripper) http.RoundTripper {
+	if base == nil {
+		base = http.DefaultTransport
+	}
+
+	return &loggingTransport{base: base}
+}
+
+func (t *loggingTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	correlationID := CorrelationIDFromContext(req.Context())
+	if correlationID != "" && req.Header.Get(CorrelationIDHeader) == "" {
+		// a RoundTripper must not modify the caller's request
+		req = req.Clone(req.Context())
+		req.Header.Set(CorrelationIDHeader, correlationID)
+	}
+
+	start := time.Now()
+	resp, err := t.base.RoundTrip(req)
+	duration := time.Since(start)
+
+	var event *zerolog.Event
+	if err != nil {
+		event = Error().Err(err)
+	} else {
+		event = WithLevel(LevelForStatus(resp.StatusCode))
+	}
+
+	event = event.
+		Str("method", req.Method).
+		Str("url", req.URL.Redacted()).
+		Dur("duration", duration)
+	if resp != nil {
+		event = event.Int("status", resp.StatusCode)
+	}
+	if correlationID != "" {
+		event = event.Str("correlation_id", correlationID)
+	}
+	event.Msg("outbound request")
+
+	return resp, err
+}
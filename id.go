@@ -0,0 +1,39 @@
+package zerolog_wrapper
+
+import (
+	"crypto/rand"
+	"fmt"
+	"sync/atomic"
+)
+
+// idGenerator holds the func() string used by newID.
+var idGenerator atomic.Value
+
+// SetIDGenerator replaces the generator of the correlation IDs assigned by
+// Middleware and the run IDs of NewJobLogger, eg: with one producing
+// time-sortable ULIDs. A nil generator restores the default random (version 4) UUIDs.
+func SetIDGenerator(generate func() string) {
+	if generate == nil {
+		generate = newUUID
+	}
+	idGenerator.Store(generate)
+}
+
+// newID returns a new ID from the configured generator.
+func newID() string {
+	if generate, ok := idGenerator.Load().(func() string); ok {
+		return generate()
+	}
+
+	return newUUID()
+}
+
+// newUUID returns a random (version 4) UUID.
+func newUUID() string {
+	var b [16]byte
+	_, _ = rand.Read(b[:])
+	b[6] = (b[6] & 0x0f) | 0x40 // version 4
+	b[8] = (b[8] & 0x3f) | 0x80 // RFC 4122 variant
+
+	return fmt.Sprintf("%x-%x-%x-%x-%x", b[0:4], b[4:6], b[6:8], b[8:10], b[10:16])
+}
@@ -0,0 +1,479 @@
+package zerolog_wrapper
+
+import (
+	"context"
+	"io"
+	"os"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/rs/zerolog"
+)
+
+// Instance is a logger with its own level, output, context and background
+// work, isolated from the global logger and from other instances, eg: for a
+// plugin or an embedded library. The package level functions such as Info
+// and SetLevel operate on a default instance set up by InitLog.
+//
+// Settings zerolog keeps globally, like WithLevelNames and the caller
+// format, still apply to every instance.
+type Instance struct {
+	// mu guards logger, which can be swapped or updated after setup
+	mu     sync.RWMutex
+	logger zerolog.Logger
+	env    Env
+	format *formatWriter
+	// output is the writer of logger, used by Critical
+	output zerolog.LevelWriter
+	// writers is the lifecycle of the writers behind output when they belong
+	// to another instance, the one a Clone was cloned from until SetOutput
+	writers *lifecycle
+	// levelStr and opts are what the instance was set up with, used by Benchmark
+	levelStr LogLevel
+	opts     *options
+
+	finalized  atomic.Bool
+	muted      atomic.Int32
+	counters   eventCounters
+	recentLogs recentLogsBuffer
+	lifecycle  lifecycle
+}
+
+// std is the default instance behind the package level functions.
+var std = &Instance{}
+
+// New returns a new Instance set up like InitLog sets up the global logger.
+//
+// eg:
+//
+//	pluginLog, err := log.New(log.DebugLevel, "prod", log.WithOutput(pluginFile))
+//	if err != nil {
+//		...
+//	}
+//	pluginLog.Info().Msg("plugin loaded")
+func New(logLevelStr LogLevel, appEnv Env, opts ...Option) (*Instance, error) {
+	o := newOptions(opts)
+	if err := o.validate(); err != nil {
+		return nil, err
+	}
+
+	if err := o.openFile(); err != nil {
+		return nil, err
+	}
+
+	setZerologGlobals()
+
+	i := &Instance{}
+	i.setup(context.Background(), logLevelStr, appEnv, o)
+
+	return i, nil
+}
+
+var zerologGlobalsOnce sync.Once
+
+// setZerologGlobals sets the process wide zerolog settings the package relies
+// on. It does so only once, as writing them races with goroutines already logging.
+func setZerologGlobals() {
+	zerologGlobalsOnce.Do(func() {
+		zerolog.CallerMarshalFunc = marshalCaller
+
+		if zerolog.ErrorHandler == nil {
+			zerolog.ErrorHandler = defaultInternalErrorHandler
+		}
+	})
+}
+
+// setup builds the logger of the instance from o. It leaves the process wide
+// zerolog settings alone, see setZerologGlobals.
+func (i *Instance) setup(ctx context.Context, logLevelStr LogLevel, appEnv Env, o *options) {
+	logLevel := toZerologLevel(logLevelStr)
+
+	var dest io.Writer = os.Stderr
+	if appEnv == Dev {
+		dest = os.Stdout
+	}
+	if o.output != nil {
+		dest = o.output
+		i.lifecycle.registerOutput(dest)
+	}
+	if o.file != nil {
+		i.lifecycle.registerCloser(o.file)
+	}
+	for _, spec := range o.outputSpecs {
+		i.lifecycle.registerOutput(spec.Writer)
+	}
+
+	// buffered right above the destinations, so the formatted events are
+	// written out in batches
+	var buffers []*bufferedWriter
+	buffer := func(w io.Writer) io.Writer {
+		if o.bufferSize <= 0 {
+			return w
+		}
+		buffered := newBufferedWriter(w, o.bufferSize)
+		i.lifecycle.registerFlusher(flusherFunc(buffered.writeOut))
+		i.lifecycle.registerCloser(buffered)
+		buffers = append(buffers, buffered)
+		return buffered
+	}
+	dest = buffer(dest)
+	specs := append([]OutputSpec(nil), o.outputSpecs...)
+	for n := range specs {
+		specs[n].Writer = buffer(specs[n].Writer)
+	}
+
+	if o.gzipLevel != nil {
+		// the level was checked by validate
+		gz, _ := newGzipWriter(dest, *o.gzipLevel)
+		i.lifecycle.registerFlusher(flusherFunc(gz.flush))
+		i.lifecycle.registerCloser(gz)
+		dest = gz
+	}
+	if o.lineEnding != "" && o.lineEnding != "\n" {
+		dest = &lineEndingWriter{out: dest, ending: []byte(o.lineEnding)}
+	}
+
+	// enforce TRACE and console output in development environment
+	format := JSONFormat
+	if appEnv == Dev {
+		logLevel = zerolog.TraceLevel
+		if o.output == nil {
+			format = ConsoleFormat
+		}
+	}
+	formatted := newFormatWriter(dest, format)
+
+	var output zerolog.LevelWriter = categoryRouter{out: newLineWriter(formatted)}
+	if len(specs) > 0 {
+		output = categoryRouter{out: newMultiOutputWriter(specs)}
+		formatted = nil
+	}
+
+	if len(buffers) > 0 {
+		if o.flushInterval > 0 {
+			i.lifecycle.registerStopper(every(o.flushInterval, flushBuffers(buffers)))
+		}
+		output = bufferFlushWriter{out: output, buffers: buffers}
+	}
+
+	// below the field transforms, so recent logs only hold what is written out
+	if o.recentLogsSize > 0 {
+		i.recentLogs.reset(o.recentLogsSize)
+		output = recentLogsWriter{out: output, recentLogs: &i.recentLogs}
+	}
+
+	if len(o.transforms) > 0 {
+		output = &fieldsWriter{out: output, transforms: o.transforms}
+	}
+
+	if o.quietBufferSize > 0 {
+		output = newQuietWriter(output, o.quietBufferSize)
+	}
+
+	if o.asyncQueueSize > 0 {
+		aw := newAsyncWriter(output, o.asyncQueueSize, &i.counters)
+		i.lifecycle.registerFlusher(aw)
+		i.lifecycle.setAsync(aw)
+		output = aw
+	}
+
+	output = statsWriter{out: timeOverrideWriter{out: output}, counters: &i.counters}
+	output = fatalFlushWriter{out: output, lifecycle: &i.lifecycle, timeout: o.fatalFlushTimeout}
+
+	logger := zerolog.New(output).
+		Level(logLevel).
+		With().
+		Timestamp().
+		Logger()
+
+	if len(o.k8sEnv) > 0 {
+		names, values := k8sFields(o.k8sEnv)
+		c := logger.With()
+		for n, name := range names {
+			c = c.Str(name, values[n])
+		}
+		logger = c.Logger()
+	}
+
+	if o.schemaVersion != nil {
+		logger = logger.With().Int("log_schema_version", *o.schemaVersion).Logger()
+	}
+
+	if o.minCallerLevel != nil {
+		logger = logger.Hook(minCallerLevelHook{level: *o.minCallerLevel})
+	} else if logLevelStr == TraceLevel || appEnv == Dev {
+		logger = logger.With().Caller().Logger()
+	}
+
+	if !o.skipCallbacks {
+		logger = logger.Hook(filterHook{})
+	}
+
+	if o.sampling != nil {
+		sampler := o.sampling.sampler(time.Now())
+		if o.onSampledOut != nil {
+			logger = logger.Hook(samplingHook{sampler: sampler, counters: &i.counters, onSampledOut: o.onSampledOut})
+		} else {
+			logger = logger.Sample(countingSampler{sampler: sampler, counters: &i.counters})
+		}
+	}
+
+	if !o.disableHostIP {
+		logger = logger.Hook(hostIPHook{})
+	}
+
+	if !o.skipCallbacks {
+		logger = logger.Hook(dynamicFieldsHook{})
+	}
+
+	for _, hook := range o.hooks {
+		logger = logger.Hook(hook)
+	}
+
+	i.mu.Lock()
+	i.logger = logger
+	i.env = appEnv
+	i.levelStr = logLevelStr
+	i.opts = o
+	i.format = formatted
+	i.output = output
+	i.mu.Unlock()
+
+	if o.cloudProvider != "" && o.cloudProvider != CloudNone {
+		go i.addCloudMetadata(ctx, o.cloudProvider)
+	}
+
+	if ctx.Done() != nil {
+		i.lifecycle.shutdownOnDone(ctx)
+	}
+}
+
+// Clone returns a copy of the global logger, see Instance.Clone.
+func Clone() *Instance {
+	return std.Clone()
+}
+
+// Clone returns a copy of the instance with the same level, hooks and context
+// fields, writing to the same output. Changing the level, context or output of
+// the copy leaves the instance untouched, and the other way round.
+//
+// eg: a component logger with its own level
+//
+//	dbLog := log.Clone()
+//	dbLog.SetLevel(log.DebugLevel)
+//	dbLog.UpdateContext(func(c zerolog.Context) zerolog.Context {
+//		return c.Str("component", "db")
+//	})
+//
+// Until SetOutput is called the events of the copy go through the output of
+// the instance, so they are counted in its Stats, written out by its Flush and
+// Shutdown and Critical on the copy flushes the writers of the instance. The
+// copy has no format of its own either, SetFormat on it has no effect until SetOutput.
+func (i *Instance) Clone() *Instance {
+	i.mu.RLock()
+	defer i.mu.RUnlock()
+
+	return &Instance{
+		// With copies the context fields into a new buffer, so neither
+		// logger's updates write into memory the other one reads
+		logger:  i.logger.With().Logger(),
+		env:     i.env,
+		output:  i.output,
+		writers: i.outputWriters(),
+	}
+}
+
+// outputWriters returns the lifecycle of the writers behind the output of the
+// instance. It must be called with mu held.
+func (i *Instance) outputWriters() *lifecycle {
+	if i.writers != nil {
+		return i.writers
+	}
+
+	return &i.lifecycle
+}
+
+// SetOutput replaces the destination of the instance with w, eg: to send the
+// events of a Clone somewhere else. Events are written as JSON, writers added by
+// options such as WithBuffer or WithAsync are not applied to w.
+func (i *Instance) SetOutput(w io.Writer) {
+	if !i.mutable("SetOutput") {
+		return
+	}
+
+	formatted := newFormatWriter(w, JSONFormat)
+	output := statsWriter{out: timeOverrideWriter{out: newLineWriter(formatted)}, counters: &i.counters}
+	i.lifecycle.registerOutput(w)
+
+	i.mu.Lock()
+	defer i.mu.Unlock()
+	i.logger = i.logger.Output(output)
+	i.format = formatted
+	i.output = output
+	i.writers = nil
+}
+
+// current returns a copy of the instance's logger which is safe to use without holding mu.
+func (i *Instance) current() zerolog.Logger {
+	if i.muted.Load() > 0 {
+		return zerolog.Nop()
+	}
+
+	i.mu.RLock()
+	defer i.mu.RUnlock()
+
+	return i.logger
+}
+
+// Mute silences the global logger until the returned function is called, see Instance.Mute.
+func Mute() (restore func()) {
+	return std.Mute()
+}
+
+// Mute silences the instance until the returned function is called, eg: while
+// a test deliberately triggers error paths:
+//
+//	defer log.Mute()()
+//
+// Mutes nest, the instance logs again once every restore function was called.
+// Level and context changes made while muted are kept. Loggers obtained
+// before, such as those stored in a context by WithContext, aren't muted.
+func (i *Instance) Mute() (restore func()) {
+	i.muted.Add(1)
+
+	var once sync.Once
+	return func() {
+		once.Do(func() { i.muted.Add(-1) })
+	}
+}
+
+// currentEnv returns the environment the instance was set up for.
+func (i *Instance) currentEnv() Env {
+	i.mu.RLock()
+	defer i.mu.RUnlock()
+
+	return i.env
+}
+
+// GetLogger returns the zerolog.Logger of the instance.
+func (i *Instance) GetLogger() zerolog.Logger {
+	return i.current()
+}
+
+// UpdateContext updates the context of the instance's logger, see UpdateContext.
+func (i *Instance) UpdateContext(update func(c zerolog.Context) zerolog.Context) {
+	if !i.mutable("UpdateContext") {
+		return
+	}
+	i.updateContext(update)
+}
+
+// UpdateContextBatch applies several context updates at once, see UpdateContextBatch.
+func (i *Instance) UpdateContextBatch(updates []func(c zerolog.Context) zerolog.Context) {
+	if !i.mutable("UpdateContextBatch") {
+		return
+	}
+	i.updateContext(func(c zerolog.Context) zerolog.Context {
+		for _, update := range updates {
+			c = update(c)
+		}
+		return c
+	})
+}
+
+// updateContext updates the logger's context regardless of FinalizeConfig.
+func (i *Instance) updateContext(update func(c zerolog.Context) zerolog.Context) {
+	i.mu.Lock()
+	defer i.mu.Unlock()
+	i.logger.UpdateContext(update)
+}
+
+// FinalizeConfig locks the configuration of the instance, see FinalizeConfig.
+func (i *Instance) FinalizeConfig() {
+	i.finalized.Store(true)
+}
+
+// mutable reports whether the instance may still be changed, warning about the attempt otherwise.
+func (i *Instance) mutable(function string) bool {
+	if i.finalized.Load() {
+		l := i.current()
+		l.Warn().Str("function", function).Msg("logger configuration is finalized, ignoring change")
+		return false
+	}
+
+	return true
+}
+
+// Trace starts a new message with trace level.
+//
+// You must call Msg on the returned event in order to send the event.
+func (i *Instance) Trace() *zerolog.Event {
+	l := i.current()
+	return l.Trace()
+}
+
+// Debug starts a new message with debug level.
+//
+// You must call Msg on the returned event in order to send the event.
+func (i *Instance) Debug() *zerolog.Event {
+	l := i.current()
+	return l.Debug()
+}
+
+// Info starts a new message with info level.
+//
+// You must call Msg on the returned event in order to send the event.
+func (i *Instance) Info() *zerolog.Event {
+	l := i.current()
+	return l.Info()
+}
+
+// Warn starts a new message with warn level.
+//
+// You must call Msg on the returned event in order to send the event.
+func (i *Instance) Warn() *zerolog.Event {
+	l := i.current()
+	return l.Warn()
+}
+
+// Error starts a new message with error level.
+//
+// You must call Msg on the returned event in order to send the event.
+func (i *Instance) Error() *zerolog.Event {
+	l := i.current()
+	return l.Error()
+}
+
+// Fatal starts a new message with fatal level, see Fatal.
+//
+// You must call Msg on the returned event in order to send the event.
+func (i *Instance) Fatal() *zerolog.Event {
+	l := i.current()
+	if !fatalExits(i.currentEnv()) {
+		return l.Error().Bool("would_exit", true)
+	}
+
+	return l.Fatal()
+}
+
+// Panic starts a new message with panic level.
+//
+// You must call Msg on the returned event in order to send the event.
+func (i *Instance) Panic() *zerolog.Event {
+	l := i.current()
+	return l.Panic()
+}
+
+// WithLevel starts a new message with level, see WithLevel.
+//
+// You must call Msg on the returned event in order to send the event.
+func (i *Instance) WithLevel(level LogLevel) *zerolog.Event {
+	l := i.current()
+	parsed, ok := parseLevel(string(level))
+	if !ok {
+		parsed = InfoLevel
+	}
+
+	return l.WithLevel(toZerologLevel(parsed))
+}
@@ -0,0 +1,57 @@
+package zerolog_wrapper
+
+import (
+	"time"
+
+	"github.com/rs/zerolog"
+)
+
+// Job is a single run of a batch or cron job, see StartJob.
+type Job struct {
+	// Logger carries the job_name and run_id fields of the run.
+	Logger zerolog.Logger
+
+	start time.Time
+}
+
+// NewJobLogger returns a logger carrying jobName as job_name and a freshly
+// generated run_id, so all logs of a single job run can be correlated.
+func NewJobLogger(jobName string) zerolog.Logger {
+	l := current()
+
+	return l.With().
+		Str("job_name", jobName).
+		Str("run_id", newID()).
+		Logger()
+}
+
+// StartJob logs the start of a run of jobName and returns the Job to log through.
+// Call EndJob once the run is done.
+//
+// eg:
+//
+//	job := log.StartJob("nightly-report")
+//	err := run(job.Logger)
+//	log.EndJob(job, err)
+func StartJob(jobName string) *Job {
+	job := &Job{
+		Logger: NewJobLogger(jobName),
+		start:  time.Now(),
+	}
+	job.Logger.Info().Msg("job started")
+
+	return job
+}
+
+// EndJob logs the end of the job run with its total duration. A nil err
+// is logged as a success at info level, anything else as a failure at error level.
+func EndJob(job *Job, err error) {
+	e := job.Logger.Info()
+	if err != nil {
+		e = job.Logger.Error().Err(err)
+	}
+
+	e.Bool("success", err == nil).
+		Dur("duration", time.Since(job.start)).
+		Msg("job finished")
+}
@@ -0,0 +1,18 @@
+package zerolog_wrapper
+
+import (
+	"errors"
+	"io"
+
+	"github.com/rs/zerolog"
+)
+
+// ErrJournalUnsupported is returned by NewJournalWriter on platforms other than Linux.
+var ErrJournalUnsupported = errors.New("zerolog_wrapper: journald is only available on linux")
+
+// JournalWriter sends events to the systemd journal, see NewJournalWriter.
+// Close closes its socket.
+type JournalWriter interface {
+	zerolog.LevelWriter
+	io.Closer
+}
@@ -0,0 +1,196 @@
+//go:build linux
+
+package zerolog_wrapper
+
+import (
+	"bytes"
+	"encoding/binary"
+	"encoding/json"
+	"errors"
+	"net"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"syscall"
+
+	"github.com/rs/zerolog"
+	"golang.org/x/sys/unix"
+)
+
+// journalSocket is the socket journald receives native protocol messages on.
+var journalSocket = "/run/systemd/journal/socket"
+
+// journalPriorities maps levels onto syslog priorities.
+var journalPriorities = map[zerolog.Level]int{
+	zerolog.TraceLevel: 7, // debug
+	zerolog.DebugLevel: 7, // debug
+	zerolog.InfoLevel:  6, // info
+	zerolog.WarnLevel:  4, // warning
+	zerolog.ErrorLevel: 3, // err
+	zerolog.FatalLevel: 2, // crit
+	zerolog.PanicLevel: 0, // emerg
+}
+
+// journalWriter sends events to journald using its native protocol.
+type journalWriter struct {
+	conn       *net.UnixConn
+	identifier string
+}
+
+// NewJournalWriter returns a writer sending events to the systemd journal,
+// to be used with WithOutput:
+//
+//	w, err := log.NewJournalWriter()
+//	if err != nil {
+//		...
+//	}
+//	log.InitLog(log.InfoLevel, "prod", log.WithOutput(w))
+//
+// Each field becomes a journal field of the same name in upper case, eg: host_ip
+// becomes HOST_IP, so it can be searched with journalctl. The message becomes
+// MESSAGE and the level sets PRIORITY. Events too large for a single datagram
+// are passed to journald through a sealed memory file, like sd_journal_send does.
+//
+// Close the writer once the logger is shut down.
+func NewJournalWriter() (JournalWriter, error) {
+	conn, err := net.DialUnix("unixgram", nil, &net.UnixAddr{Name: journalSocket, Net: "unixgram"})
+	if err != nil {
+		return nil, err
+	}
+
+	return &journalWriter{
+		conn:       conn,
+		identifier: filepath.Base(os.Args[0]),
+	}, nil
+}
+
+func (w *journalWriter) Write(p []byte) (int, error) {
+	return w.WriteLevel(zerolog.NoLevel, p)
+}
+
+func (w *journalWriter) WriteLevel(level zerolog.Level, p []byte) (int, error) {
+	var msg bytes.Buffer
+
+	if priority, ok := journalPriorities[level]; ok {
+		appendJournalField(&msg, "PRIORITY", strconv.Itoa(priority))
+	}
+	appendJournalField(&msg, "SYSLOG_IDENTIFIER", w.identifier)
+
+	fields, err := decodeFields(p)
+	if err != nil {
+		appendJournalField(&msg, "MESSAGE", strings.TrimSuffix(string(p), "\n"))
+	}
+	for _, field := range fields {
+		key := journalFieldName(field.key)
+		switch field.key {
+		case zerolog.MessageFieldName:
+			key = "MESSAGE"
+		case zerolog.LevelFieldName:
+			key = "LEVEL"
+		}
+
+		value := string(field.value)
+		var s string
+		if json.Unmarshal(field.value, &s) == nil {
+			value = s
+		}
+		appendJournalField(&msg, key, value)
+	}
+
+	if err := w.send(msg.Bytes()); err != nil {
+		return 0, err
+	}
+
+	return len(p), nil
+}
+
+func (w *journalWriter) Close() error {
+	return w.conn.Close()
+}
+
+// send writes msg to journald, falling back to sendFile when the socket
+// refuses msg as too large.
+func (w *journalWriter) send(msg []byte) error {
+	_, err := w.conn.Write(msg)
+	if errors.Is(err, syscall.EMSGSIZE) || errors.Is(err, syscall.ENOBUFS) {
+		return w.sendFile(msg)
+	}
+
+	return err
+}
+
+// sendFile passes msg to journald as a sealed memfd, which journald reads
+// the message from.
+func (w *journalWriter) sendFile(msg []byte) error {
+	fd, err := unix.MemfdCreate("journal-message", unix.MFD_CLOEXEC|unix.MFD_ALLOW_SEALING)
+	if err != nil {
+		return err
+	}
+	f := os.NewFile(uintptr(fd), "journal-message")
+	defer f.Close()
+
+	if _, err := f.Write(msg); err != nil {
+		return err
+	}
+	// journald only accepts files which can't change anymore
+	seals := unix.F_SEAL_SHRINK | unix.F_SEAL_GROW | unix.F_SEAL_WRITE | unix.F_SEAL_SEAL
+	if _, err := unix.FcntlInt(f.Fd(), unix.F_ADD_SEALS, seals); err != nil {
+		return err
+	}
+
+	// net refuses WriteMsgUnix on connected datagram sockets, send it directly
+	raw, err := w.conn.SyscallConn()
+	if err != nil {
+		return err
+	}
+	rights := unix.UnixRights(int(f.Fd()))
+	var sendErr error
+	if err := raw.Write(func(s uintptr) bool {
+		sendErr = unix.Sendmsg(int(s), nil, rights, nil, 0)
+		return sendErr != unix.EAGAIN
+	}); err != nil {
+		return err
+	}
+
+	return sendErr
+}
+
+// journalFieldName turns key into a valid journal field name: upper case
+// letters, digits and underscores, not starting with an underscore or digit.
+func journalFieldName(key string) string {
+	name := strings.Map(func(r rune) rune {
+		switch {
+		case r >= 'a' && r <= 'z':
+			return r - 'a' + 'A'
+		case r >= 'A' && r <= 'Z', r >= '0' && r <= '9':
+			return r
+		default:
+			return '_'
+		}
+	}, key)
+
+	name = strings.TrimLeft(name, "_")
+	if name == "" || (name[0] >= '0' && name[0] <= '9') {
+		name = "F_" + name
+	}
+
+	return name
+}
+
+// appendJournalField appends a field in the native protocol format, using
+// the length prefixed form for values containing newlines.
+func appendJournalField(buf *bytes.Buffer, key, value string) {
+	buf.WriteString(key)
+	if !strings.Contains(value, "\n") {
+		buf.WriteByte('=')
+		buf.WriteString(value)
+		buf.WriteByte('\n')
+		return
+	}
+
+	buf.WriteByte('\n')
+	_ = binary.Write(buf, binary.LittleEndian, uint64(len(value)))
+	buf.WriteString(value)
+	buf.WriteByte('\n')
+}
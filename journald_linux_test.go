@@ -0,0 +1,167 @@
+//go:build linux
+
+package zerolog_wrapper
+
+import (
+	"bytes"
+	"encoding/binary"
+	"net"
+	"os"
+	"path/filepath"
+	"strings"
+	"syscall"
+	"testing"
+
+	"github.com/rs/zerolog"
+	"golang.org/x/sys/unix"
+)
+
+func TestJournalFieldName(t *testing.T) {
+	for key, want := range map[string]string{
+		"host_ip":        "HOST_IP",
+		"Request.Method": "REQUEST_METHOD",
+		"_private":       "PRIVATE",
+		"2fa":            "F_2FA",
+		"___":            "F_",
+		"ünïcode":        "N_CODE",
+	} {
+		if got := journalFieldName(key); got != want {
+			t.Errorf("journalFieldName(%q) = %q, want %q", key, got, want)
+		}
+	}
+}
+
+func TestAppendJournalField(t *testing.T) {
+	var buf bytes.Buffer
+	appendJournalField(&buf, "MESSAGE", "one line")
+	if got := buf.String(); got != "MESSAGE=one line\n" {
+		t.Errorf("single line field = %q", got)
+	}
+
+	buf.Reset()
+	appendJournalField(&buf, "STACK", "two\nlines")
+	want := []byte("STACK\n")
+	want = binary.LittleEndian.AppendUint64(want, uint64(len("two\nlines")))
+	want = append(want, "two\nlines\n"...)
+	if !bytes.Equal(buf.Bytes(), want) {
+		t.Errorf("multi line field = %q, want %q", buf.Bytes(), want)
+	}
+}
+
+// listenJournal points journalSocket to a socket the test reads the messages from.
+func listenJournal(t *testing.T) *net.UnixConn {
+	t.Helper()
+
+	path := filepath.Join(t.TempDir(), "journal.socket")
+	conn, err := net.ListenUnixgram("unixgram", &net.UnixAddr{Name: path, Net: "unixgram"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { conn.Close() })
+
+	previous := journalSocket
+	journalSocket = path
+	t.Cleanup(func() { journalSocket = previous })
+
+	return conn
+}
+
+// readJournal reads a message sent to conn, either inline or as a passed file.
+func readJournal(t *testing.T, conn *net.UnixConn) []byte {
+	t.Helper()
+
+	buf := make([]byte, 64*1024)
+	oob := make([]byte, unix.CmsgSpace(4))
+	n, oobn, _, _, err := conn.ReadMsgUnix(buf, oob)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if oobn == 0 {
+		return buf[:n]
+	}
+
+	msgs, err := unix.ParseSocketControlMessage(oob[:oobn])
+	if err != nil {
+		t.Fatal(err)
+	}
+	fds, err := unix.ParseUnixRights(&msgs[0])
+	if err != nil {
+		t.Fatal(err)
+	}
+	f := os.NewFile(uintptr(fds[0]), "journal-message")
+	defer f.Close()
+
+	seals, err := unix.FcntlInt(f.Fd(), unix.F_GET_SEALS, 0)
+	if err != nil || seals&unix.F_SEAL_WRITE == 0 {
+		t.Errorf("passed file isn't sealed: %#x, %v", seals, err)
+	}
+
+	var msg bytes.Buffer
+	if _, err := f.Seek(0, 0); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := msg.ReadFrom(f); err != nil {
+		t.Fatal(err)
+	}
+	return msg.Bytes()
+}
+
+func TestJournalWriter(t *testing.T) {
+	journal := listenJournal(t)
+
+	w, err := NewJournalWriter()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer w.Close()
+
+	if _, err := w.WriteLevel(zerolog.WarnLevel, []byte(`{"level":"warn","user_id":42,"message":"slow"}`+"\n")); err != nil {
+		t.Fatal(err)
+	}
+
+	msg := string(readJournal(t, journal))
+	for _, field := range []string{"PRIORITY=4\n", "LEVEL=warn\n", "USER_ID=42\n", "MESSAGE=slow\n", "SYSLOG_IDENTIFIER="} {
+		if !strings.Contains(msg, field) {
+			t.Errorf("%q missing from %q", field, msg)
+		}
+	}
+}
+
+func TestJournalWriterLargeEvent(t *testing.T) {
+	journal := listenJournal(t)
+
+	w, err := NewJournalWriter()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer w.Close()
+
+	// larger than the socket's send buffer
+	sndbuf, err := syscall.GetsockoptInt(socketFd(t, w.(*journalWriter).conn), syscall.SOL_SOCKET, syscall.SO_SNDBUF)
+	if err != nil {
+		t.Fatal(err)
+	}
+	large := strings.Repeat("x", 2*sndbuf)
+	if _, err := w.WriteLevel(zerolog.InfoLevel, []byte(`{"level":"info","message":"`+large+`"}`)); err != nil {
+		t.Fatal(err)
+	}
+
+	if msg := readJournal(t, journal); !bytes.Contains(msg, []byte("MESSAGE="+large+"\n")) {
+		t.Errorf("large message lost, got %d bytes", len(msg))
+	}
+}
+
+// socketFd returns the file descriptor of conn.
+func socketFd(t *testing.T, conn *net.UnixConn) int {
+	t.Helper()
+
+	raw, err := conn.SyscallConn()
+	if err != nil {
+		t.Fatal(err)
+	}
+	var fd int
+	if err := raw.Control(func(f uintptr) { fd = int(f) }); err != nil {
+		t.Fatal(err)
+	}
+	return fd
+}
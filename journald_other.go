@@ -0,0 +1,8 @@
+//go:build !linux
+
+package zerolog_wrapper
+
+// NewJournalWriter is only supported on Linux, elsewhere it always returns ErrJournalUnsupported.
+func NewJournalWriter() (JournalWriter, error) {
+	return nil, ErrJournalUnsupported
+}
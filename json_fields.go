@@ -0,0 +1,92 @@
+package zerolog_wrapper
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+
+	"github.com/rs/zerolog"
+)
+
+// jsonField is a single top level field of a serialized event.
+type jsonField struct {
+	key   string
+	value json.RawMessage
+}
+
+// decodeFields splits a serialized JSON event into its top level fields,
+// keeping them in the order they were written.
+func decodeFields(p []byte) ([]jsonField, error) {
+	dec := json.NewDecoder(bytes.NewReader(p))
+	if tok, err := dec.Token(); err != nil || tok != json.Delim('{') {
+		return nil, errors.New("event is not a JSON object")
+	}
+
+	var fields []jsonField
+	for dec.More() {
+		tok, err := dec.Token()
+		if err != nil {
+			return nil, err
+		}
+		var value json.RawMessage
+		if err := dec.Decode(&value); err != nil {
+			return nil, err
+		}
+		fields = append(fields, jsonField{key: tok.(string), value: value})
+	}
+
+	return fields, nil
+}
+
+// encodeFields serializes fields into a newline terminated JSON object.
+func encodeFields(fields []jsonField) []byte {
+	var buf bytes.Buffer
+	enc := json.NewEncoder(&buf)
+	enc.SetEscapeHTML(false)
+
+	buf.WriteByte('{')
+	for i, field := range fields {
+		if i > 0 {
+			buf.WriteByte(',')
+		}
+		_ = enc.Encode(field.key)
+		buf.Truncate(buf.Len() - 1) // Encode appends a newline
+		buf.WriteByte(':')
+		buf.Write(field.value)
+	}
+	buf.WriteString("}\n")
+
+	return buf.Bytes()
+}
+
+// fieldTransform rewrites the top level fields of an event.
+type fieldTransform func(fields []jsonField) []jsonField
+
+// fieldsWriter applies transforms, in order, to each event before passing it on.
+// Anything that isn't a JSON object is passed on untouched.
+type fieldsWriter struct {
+	out        zerolog.LevelWriter
+	transforms []fieldTransform
+}
+
+func (w *fieldsWriter) Write(p []byte) (int, error) {
+	return w.WriteLevel(zerolog.NoLevel, p)
+}
+
+func (w *fieldsWriter) WriteLevel(level zerolog.Level, p []byte) (int, error) {
+	fields, err := decodeFields(p)
+	if err != nil {
+		return w.out.WriteLevel(level, p)
+	}
+
+	for _, transform := range w.transforms {
+		fields = transform(fields)
+	}
+
+	if _, err := w.out.WriteLevel(level, encodeFields(fields)); err != nil {
+		return 0, err
+	}
+
+	// report the original length, zerolog treats anything else as a short write
+	return len(p), nil
+}
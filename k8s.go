@@ -0,0 +1,61 @@
+package zerolog_wrapper
+
+import (
+	"os"
+	"sort"
+)
+
+// DefaultK8sEnv maps the fields added by InitLogK8s onto the environment
+// variables they are read from, as commonly exposed through the downward API.
+var DefaultK8sEnv = map[string]string{
+	"pod_name":  "POD_NAME",
+	"namespace": "POD_NAMESPACE",
+	"node_name": "NODE_NAME",
+}
+
+// InitLogK8s initializes the global logger like InitLog, adding the
+// Kubernetes fields of DefaultK8sEnv to every event, see WithK8sFields.
+//
+// The variables are set in the pod spec from the downward API:
+//
+//	env:
+//	  - name: POD_NAME
+//	    valueFrom: {fieldRef: {fieldPath: metadata.name}}
+//	  - name: POD_NAMESPACE
+//	    valueFrom: {fieldRef: {fieldPath: metadata.namespace}}
+//	  - name: NODE_NAME
+//	    valueFrom: {fieldRef: {fieldPath: spec.nodeName}}
+func InitLogK8s(logLevelStr LogLevel, appEnv Env, opts ...Option) {
+	InitLog(logLevelStr, appEnv, append([]Option{WithK8sFields(nil)}, opts...)...)
+}
+
+// WithK8sFields adds a field to every event for each entry of env, mapping
+// the field name onto the environment variable holding its value. A nil env
+// uses DefaultK8sEnv. Variables which aren't set are skipped.
+func WithK8sFields(env map[string]string) Option {
+	if env == nil {
+		env = DefaultK8sEnv
+	}
+
+	return func(o *options) {
+		o.k8sEnv = env
+	}
+}
+
+// k8sFields returns the field names and values of env which are set, sorted by field name.
+func k8sFields(env map[string]string) (names, values []string) {
+	for name := range env {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	set := names[:0]
+	for _, name := range names {
+		if value, ok := os.LookupEnv(env[name]); ok && value != "" {
+			set = append(set, name)
+			values = append(values, value)
+		}
+	}
+
+	return set, values
+}
@@ -0,0 +1,43 @@
+package zerolog_wrapper
+
+import (
+	"sync"
+	"sync/atomic"
+)
+
+// KeyedSampler samples by a key such as the route of a request, eg: to
+// sample the access logs of a busy health check endpoint while keeping all
+// of the rare ones. A rate of N keeps one in every N events of a key, 0 and
+// 1 keep all of them. Keys missing from Rates use DefaultRate and share a
+// single counter, so arbitrary keys don't grow the sampler's memory.
+//
+// eg:
+//
+//	sampler := &log.KeyedSampler{Rates: map[string]uint32{"/health": 100}}
+//	http.ListenAndServe(":8080", log.Middleware(log.WithAccessLogSampling(sampler))(handler))
+type KeyedSampler struct {
+	Rates       map[string]uint32
+	DefaultRate uint32
+
+	counters sync.Map // key -> *atomic.Uint32
+	fallback atomic.Uint32
+}
+
+// Sample reports whether the next event of key is kept.
+func (s *KeyedSampler) Sample(key string) bool {
+	rate, ok := s.Rates[key]
+	if !ok {
+		rate = s.DefaultRate
+	}
+	if rate <= 1 {
+		return true
+	}
+
+	counter := &s.fallback
+	if ok {
+		c, _ := s.counters.LoadOrStore(key, new(atomic.Uint32))
+		counter = c.(*atomic.Uint32)
+	}
+
+	return counter.Add(1)%rate == 1
+}
@@ -0,0 +1,39 @@
+package zerolog_wrapper
+
+import (
+	"fmt"
+
+	"github.com/rs/zerolog"
+)
+
+// KV adds pairs of alternating keys and values to e as typed fields, easing
+// the migration from key value based loggers such as logrus. Keys which
+// aren't strings are formatted with fmt. An odd number of arguments logs a
+// warning and drops the dangling key.
+//
+// eg:
+//
+//	log.KV(log.Info(), "user", userID, "attempts", 3).Msg("login")
+//	// Output: {"level":"info","user":"42","attempts":3,"message":"login"}
+func KV(e *zerolog.Event, pairs ...interface{}) *zerolog.Event {
+	if e == nil {
+		return e
+	}
+
+	if len(pairs)%2 == 1 {
+		l := current()
+		l.Warn().Interface("key", pairs[len(pairs)-1]).Msg("KV called with a key without value, dropping it")
+		pairs = pairs[:len(pairs)-1]
+	}
+
+	fields := make([]interface{}, len(pairs))
+	for i := 0; i < len(pairs); i += 2 {
+		key, ok := pairs[i].(string)
+		if !ok {
+			key = fmt.Sprint(pairs[i])
+		}
+		fields[i], fields[i+1] = key, pairs[i+1]
+	}
+
+	return e.Fields(fields)
+}
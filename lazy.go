@@ -0,0 +1,16 @@
+package zerolog_wrapper
+
+import "github.com/rs/zerolog"
+
+// Lazy adds the value returned by fn under key. Passed to the Func method of an
+// event, fn is only called when the event is enabled, so expensive values cost
+// nothing while their level is turned off.
+//
+// eg:
+//
+//	log.Debug().Func(log.Lazy("dump", func() interface{} { return expensive() })).Msg("state")
+func Lazy(key string, fn func() interface{}) func(e *zerolog.Event) {
+	return func(e *zerolog.Event) {
+		e.Interface(key, fn())
+	}
+}
@@ -0,0 +1,176 @@
+package zerolog_wrapper
+
+import (
+	"os"
+	"os/signal"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/rs/zerolog"
+)
+
+// levelFilePollInterval is how often WatchLevelFile checks the level file for changes.
+var levelFilePollInterval = 5 * time.Second
+
+// toZerologLevel maps a LogLevel onto the matching zerolog level.
+func toZerologLevel(logLevelStr LogLevel) zerolog.Level {
+	switch logLevelStr {
+	case TraceLevel:
+		return zerolog.TraceLevel
+	case DebugLevel:
+		return zerolog.DebugLevel
+	case InfoLevel:
+		return zerolog.InfoLevel
+	case WarnLevel:
+		return zerolog.WarnLevel
+	case ErrorLevel:
+		return zerolog.ErrorLevel
+	case FatalLevel:
+		return zerolog.FatalLevel
+	case PanicLevel:
+		return zerolog.PanicLevel
+	default:
+		return zerolog.InfoLevel // default to INFO
+	}
+}
+
+// parseLevel parses a level name such as "debug", reporting whether it is a known level.
+func parseLevel(levelStr string) (LogLevel, bool) {
+	level := LogLevel(strings.ToLower(strings.TrimSpace(levelStr)))
+	switch level {
+	case TraceLevel, DebugLevel, InfoLevel, WarnLevel, ErrorLevel, FatalLevel, PanicLevel:
+		return level, true
+	default:
+		return "", false
+	}
+}
+
+// SetLevel changes the level of the global logger at runtime.
+func SetLevel(logLevelStr LogLevel) {
+	std.SetLevel(logLevelStr)
+}
+
+// SetLevel changes the level of the instance at runtime.
+func (i *Instance) SetLevel(logLevelStr LogLevel) {
+	if !i.mutable("SetLevel") {
+		return
+	}
+
+	i.mu.Lock()
+	i.logger = i.logger.Level(toZerologLevel(logLevelStr))
+	i.mu.Unlock()
+}
+
+// currentLevel returns the level of the global logger.
+func currentLevel() LogLevel {
+	l := current()
+	return LogLevel(l.GetLevel().String())
+}
+
+// WatchLevelFile polls the file at path and applies the level named in it
+// through SetLevel whenever its content changes. Unknown level names are
+// reported as a warning and ignored.
+//
+// The returned function stops the watcher and waits for it to exit.
+//
+// eg:
+//
+//	stop := log.WatchLevelFile("/etc/myapp/log-level")
+//	defer stop()
+func WatchLevelFile(path string) (stop func()) {
+	done := make(chan struct{})
+	var wg sync.WaitGroup
+
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+
+		ticker := time.NewTicker(levelFilePollInterval)
+		defer ticker.Stop()
+
+		var last string
+		for {
+			if content, err := os.ReadFile(path); err == nil && string(content) != last {
+				last = string(content)
+				if level, ok := parseLevel(last); ok {
+					if level != currentLevel() {
+						SetLevel(level)
+						// SetLevel is ignored once the configuration is finalized
+						if level == currentLevel() {
+							Info().Str("path", path).Str("level", string(level)).Msg("log level changed")
+						}
+					}
+				} else {
+					Warn().Str("path", path).Str("content", last).Msg("unknown log level in level file")
+				}
+			}
+
+			select {
+			case <-done:
+				return
+			case <-ticker.C:
+			}
+		}
+	}()
+
+	var stopOnce sync.Once
+	return func() {
+		stopOnce.Do(func() {
+			close(done)
+			wg.Wait()
+		})
+	}
+}
+
+// CycleLevelOnSignal makes each of the given signals (eg: syscall.SIGUSR1)
+// switch the global logger to the next more verbose level. After trace it
+// wraps around to the level that was active when the handler was installed.
+// Without signals it handles SIGUSR1, on Windows it then does nothing.
+//
+// The returned function removes the signal handler.
+func CycleLevelOnSignal(sig ...os.Signal) (stop func()) {
+	if len(sig) == 0 {
+		sig = defaultCycleLevelSignals
+	}
+	if len(sig) == 0 {
+		return func() {}
+	}
+
+	base := currentLevel()
+	sigs := make(chan os.Signal, 1)
+	done := make(chan struct{})
+	var wg sync.WaitGroup
+
+	signal.Notify(sigs, sig...)
+
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		for {
+			select {
+			case <-done:
+				return
+			case <-sigs:
+				previous, next := currentLevel(), base
+				if level := toZerologLevel(previous); level > zerolog.TraceLevel {
+					next = LogLevel((level - 1).String())
+				}
+				SetLevel(next)
+				// SetLevel is ignored once the configuration is finalized
+				if next != previous && next == currentLevel() {
+					Info().Str("level", string(next)).Msg("log level changed")
+				}
+			}
+		}
+	}()
+
+	var stopOnce sync.Once
+	return func() {
+		stopOnce.Do(func() {
+			signal.Stop(sigs)
+			close(done)
+			wg.Wait()
+		})
+	}
+}
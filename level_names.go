@@ -0,0 +1,39 @@
+package zerolog_wrapper
+
+import "github.com/rs/zerolog"
+
+// WithLevelNames replaces the names written to the level field, eg: to emit
+// "WARNING" instead of "warn". Levels missing from names keep their default name.
+//
+// zerolog keeps this setting globally, so it applies to every zerolog logger
+// in the process. It is therefore only honoured by InitLog and the functions
+// initializing the global logger, New ignores it.
+//
+// eg:
+//
+//	log.InitLog(log.InfoLevel, "prod", log.WithLevelNames(map[zerolog.Level]string{
+//		zerolog.WarnLevel:  "WARNING",
+//		zerolog.FatalLevel: "CRITICAL",
+//	}))
+func WithLevelNames(names map[zerolog.Level]string) Option {
+	// copied, so changing names afterwards doesn't race with logging
+	copied := make(map[zerolog.Level]string, len(names))
+	for level, name := range names {
+		copied[level] = name
+	}
+
+	return func(o *options) {
+		o.levelNames = copied
+	}
+}
+
+// levelNameMarshaler returns a zerolog.LevelFieldMarshalFunc using names.
+func levelNameMarshaler(names map[zerolog.Level]string) func(l zerolog.Level) string {
+	return func(l zerolog.Level) string {
+		if name, ok := names[l]; ok {
+			return name
+		}
+
+		return l.String()
+	}
+}
@@ -0,0 +1,64 @@
+package zerolog_wrapper
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/rs/zerolog"
+)
+
+func TestWithLevelNames(t *testing.T) {
+	previous := zerolog.LevelFieldMarshalFunc
+	t.Cleanup(func() { zerolog.LevelFieldMarshalFunc = previous })
+
+	names := map[zerolog.Level]string{
+		zerolog.TraceLevel: "TRACE",
+		zerolog.DebugLevel: "DEBUG",
+		zerolog.InfoLevel:  "INFO",
+		zerolog.WarnLevel:  "WARNING",
+		zerolog.ErrorLevel: "ERROR",
+		zerolog.FatalLevel: "CRITICAL",
+		zerolog.PanicLevel: "EMERGENCY",
+	}
+
+	resetStd(t)
+	var buf bytes.Buffer
+	InitLog(TraceLevel, Prod, DisableHostIP(), WithOutput(&buf), WithLevelNames(names))
+
+	// the option holds a copy, changing the map afterwards has no effect
+	names[zerolog.InfoLevel] = "CHANGED"
+
+	logger := GetLogger()
+	for _, level := range []zerolog.Level{
+		zerolog.TraceLevel, zerolog.DebugLevel, zerolog.InfoLevel, zerolog.WarnLevel,
+		zerolog.ErrorLevel, zerolog.FatalLevel, zerolog.PanicLevel,
+	} {
+		buf.Reset()
+		// WithLevel neither exits nor panics at fatal and panic level
+		logger.WithLevel(level).Msg("remapped")
+
+		want := names[level]
+		if level == zerolog.InfoLevel {
+			want = "INFO"
+		}
+		if !bytes.Contains(buf.Bytes(), []byte(`"level":"`+want+`"`)) {
+			t.Errorf("%s: got %s, want level %q", level, buf.String(), want)
+		}
+	}
+}
+
+func TestNewIgnoresLevelNames(t *testing.T) {
+	previous := zerolog.LevelFieldMarshalFunc
+	t.Cleanup(func() { zerolog.LevelFieldMarshalFunc = previous })
+
+	var buf bytes.Buffer
+	l, err := New(InfoLevel, Prod, DisableHostIP(), WithOutput(&buf), WithLevelNames(map[zerolog.Level]string{zerolog.InfoLevel: "INFO"}))
+	if err != nil {
+		t.Fatal(err)
+	}
+	l.Info().Msg("default name")
+
+	if !bytes.Contains(buf.Bytes(), []byte(`"level":"info"`)) {
+		t.Errorf("New changed the process wide level names: %s", buf.String())
+	}
+}
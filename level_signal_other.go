@@ -0,0 +1,11 @@
+//go:build !windows
+
+package zerolog_wrapper
+
+import (
+	"os"
+	"syscall"
+)
+
+// defaultCycleLevelSignals are handled by CycleLevelOnSignal when it is given no signals.
+var defaultCycleLevelSignals = []os.Signal{syscall.SIGUSR1}
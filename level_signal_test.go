@@ -0,0 +1,44 @@
+//go:build !windows
+
+package zerolog_wrapper
+
+import (
+	"bytes"
+	"syscall"
+	"testing"
+	"time"
+)
+
+func TestCycleLevelOnSignalDefaultsToSIGUSR1(t *testing.T) {
+	useStd(t)
+	stop := CycleLevelOnSignal()
+	defer stop()
+
+	if err := syscall.Kill(syscall.Getpid(), syscall.SIGUSR1); err != nil {
+		t.Fatal(err)
+	}
+	if !waitFor(t, func() bool { return currentLevel() == DebugLevel }) {
+		t.Errorf("level = %s after SIGUSR1, want debug", currentLevel())
+	}
+}
+
+func TestCycleLevelOnSignalFinalized(t *testing.T) {
+	var buf lockedBuffer
+	useStdOutput(t, &buf)
+	stop := CycleLevelOnSignal(syscall.SIGUSR1)
+	defer stop()
+	std.finalized.Store(true)
+
+	if err := syscall.Kill(syscall.Getpid(), syscall.SIGUSR1); err != nil {
+		t.Fatal(err)
+	}
+	waitFor(t, func() bool { return bytes.Contains(buf.Bytes(), []byte("finalized")) })
+	time.Sleep(20 * time.Millisecond)
+
+	if currentLevel() != InfoLevel {
+		t.Errorf("level changed to %s after FinalizeConfig", currentLevel())
+	}
+	if bytes.Contains(buf.Bytes(), []byte("log level changed")) {
+		t.Errorf("ignored change logged as a level change: %s", buf.Bytes())
+	}
+}
@@ -0,0 +1,9 @@
+//go:build windows
+
+package zerolog_wrapper
+
+import "os"
+
+// defaultCycleLevelSignals are handled by CycleLevelOnSignal when it is given
+// no signals. Windows has no user defined signals to take over.
+var defaultCycleLevelSignals []os.Signal
@@ -0,0 +1,63 @@
+package zerolog_wrapper
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func TestWatchLevelFile(t *testing.T) {
+	var buf lockedBuffer
+	useStdOutput(t, &buf)
+
+	path := filepath.Join(t.TempDir(), "level")
+	if err := os.WriteFile(path, []byte("debug\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	stop := WatchLevelFile(path)
+	defer stop()
+
+	if !waitFor(t, func() bool { return bytes.Contains(buf.Bytes(), []byte("log level changed")) }) {
+		t.Fatalf("level change not logged: %s", buf.Bytes())
+	}
+	if currentLevel() != DebugLevel {
+		t.Errorf("level = %s, want debug", currentLevel())
+	}
+}
+
+func TestWatchLevelFileFinalized(t *testing.T) {
+	var buf lockedBuffer
+	useStdOutput(t, &buf)
+	std.finalized.Store(true)
+
+	path := filepath.Join(t.TempDir(), "level")
+	if err := os.WriteFile(path, []byte("debug\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	stop := WatchLevelFile(path)
+	waitFor(t, func() bool { return bytes.Contains(buf.Bytes(), []byte("finalized")) })
+	stop()
+
+	if currentLevel() != InfoLevel {
+		t.Errorf("level changed to %s after FinalizeConfig", currentLevel())
+	}
+	if bytes.Contains(buf.Bytes(), []byte("log level changed")) {
+		t.Errorf("ignored change logged as a level change: %s", buf.Bytes())
+	}
+}
+
+// waitFor polls cond for up to a second.
+func waitFor(t *testing.T, cond func() bool) bool {
+	t.Helper()
+
+	for deadline := time.Now().Add(time.Second); time.Now().Before(deadline); time.Sleep(5 * time.Millisecond) {
+		if cond() {
+			return true
+		}
+	}
+	return cond()
+}
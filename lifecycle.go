@@ -0,0 +1,179 @@
+package zerolog_wrapper
+
+import (
+	"context"
+	"errors"
+	"io"
+	"sync"
+	"time"
+)
+
+// lifecycle tracks the buffering writers and background work of an Instance.
+type lifecycle struct {
+	mu       sync.Mutex
+	flushers []flusher
+	stoppers []func()
+	closers  []io.Closer
+	syncers  []syncer
+	async    *asyncWriter
+}
+
+// flusherFunc adapts a function to the flusher interface.
+type flusherFunc func() error
+
+func (f flusherFunc) Flush() error {
+	return f()
+}
+
+// registerFlusher adds f to the writers flushed by Flush and Shutdown.
+// Writers must be registered from the output inwards.
+func (lc *lifecycle) registerFlusher(f flusher) {
+	lc.mu.Lock()
+	defer lc.mu.Unlock()
+
+	lc.flushers = append(lc.flushers, f)
+}
+
+// contextFlusher is implemented by outputs whose flush is bounded by a
+// context, like stream.Writer. Unlike a flusher, wrapping writers don't
+// flush it after every event, so it keeps its batching.
+type contextFlusher interface {
+	Flush(ctx context.Context) error
+}
+
+// registerOutput adds the output w to the writers synced after a critical
+// event and to those flushed by Flush and Shutdown, as far as w supports it.
+// Outputs are registered before the writers wrapping them.
+func (lc *lifecycle) registerOutput(w io.Writer) {
+	lc.registerSyncer(w)
+	if f, ok := w.(contextFlusher); ok {
+		lc.registerFlusher(flusherFunc(func() error { return f.Flush(context.Background()) }))
+	}
+}
+
+// registerStopper adds stop to the background work ended by Shutdown.
+func (lc *lifecycle) registerStopper(stop func()) {
+	lc.mu.Lock()
+	defer lc.mu.Unlock()
+
+	lc.stoppers = append(lc.stoppers, stop)
+}
+
+// registerCloser adds c to the writers closed by Shutdown once everything is flushed.
+// Like flushers, closers must be registered from the output inwards, they are
+// closed the other way round so each writer is closed before the one it writes to.
+func (lc *lifecycle) registerCloser(c io.Closer) {
+	lc.mu.Lock()
+	defer lc.mu.Unlock()
+
+	lc.closers = append(lc.closers, c)
+}
+
+// setAsync sets the writer drained by ShutdownWithTimeout.
+func (lc *lifecycle) setAsync(aw *asyncWriter) {
+	lc.mu.Lock()
+	defer lc.mu.Unlock()
+
+	lc.async = aw
+}
+
+func (lc *lifecycle) flush() error {
+	lc.mu.Lock()
+	fs := append([]flusher(nil), lc.flushers...)
+	lc.mu.Unlock()
+
+	var errs []error
+	// flush from the logger towards the output, so events flushed by
+	// one writer are flushed by the ones behind it as well
+	for i := len(fs) - 1; i >= 0; i-- {
+		if err := fs[i].Flush(); err != nil {
+			errs = append(errs, err)
+		}
+	}
+
+	return errors.Join(errs...)
+}
+
+func (lc *lifecycle) shutdown(timeout time.Duration) (DrainStats, error) {
+	lc.mu.Lock()
+	ss := lc.stoppers
+	lc.stoppers = nil
+	aw := lc.async
+	cs := append([]io.Closer(nil), lc.closers...)
+	lc.mu.Unlock()
+
+	for _, stop := range ss {
+		stop()
+	}
+
+	var stats DrainStats
+	if aw != nil {
+		stats = aw.close(timeout)
+		if stats.Pending > 0 {
+			// the remaining events are still being written, flushing would race with them
+			return stats, nil
+		}
+	}
+
+	errs := []error{lc.flush()}
+	for i := len(cs) - 1; i >= 0; i-- {
+		errs = append(errs, cs[i].Close())
+	}
+
+	return stats, errors.Join(errs...)
+}
+
+// shutdownOnDone shuts down once ctx is done, unless shutdown was called before.
+func (lc *lifecycle) shutdownOnDone(ctx context.Context) {
+	done := make(chan struct{})
+	lc.registerStopper(func() { close(done) })
+
+	go func() {
+		select {
+		case <-ctx.Done():
+			_, _ = lc.shutdown(0)
+		case <-done:
+		}
+	}()
+}
+
+// Flush writes out any events still held by buffering writers.
+func Flush() error {
+	return std.Flush()
+}
+
+// Shutdown stops the background work started by InitLog and flushes all
+// buffering writers so no events are lost. Call it before the program exits:
+//
+//	defer log.Shutdown()
+//
+// Shutdown waits for every event queued by WithAsync to be written, use
+// ShutdownWithTimeout to bound that wait.
+func Shutdown() error {
+	return std.Shutdown()
+}
+
+// ShutdownWithTimeout works like Shutdown, but waits at most timeout for the
+// events queued by WithAsync to be written. The returned DrainStats tell how
+// many were written and how many were still pending when the timeout hit,
+// so lost events on shutdown can be alerted on. A zero timeout waits without limit.
+func ShutdownWithTimeout(timeout time.Duration) (DrainStats, error) {
+	return std.ShutdownWithTimeout(timeout)
+}
+
+// Flush writes out any events still held by the instance's buffering writers.
+func (i *Instance) Flush() error {
+	return i.lifecycle.flush()
+}
+
+// Shutdown stops the background work of the instance and flushes its buffering writers, see Shutdown.
+func (i *Instance) Shutdown() error {
+	_, err := i.lifecycle.shutdown(0)
+
+	return err
+}
+
+// ShutdownWithTimeout works like Shutdown, with the wait for queued events bounded by timeout, see ShutdownWithTimeout.
+func (i *Instance) ShutdownWithTimeout(timeout time.Duration) (DrainStats, error) {
+	return i.lifecycle.shutdown(timeout)
+}
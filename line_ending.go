@@ -0,0 +1,53 @@
+package zerolog_wrapper
+
+import (
+	"bytes"
+	"io"
+
+	"github.com/rs/zerolog"
+)
+
+// WithLineEnding sets the line ending written after each event, eg: "\r\n"
+// for log files consumed by Windows tooling. The default is "\n".
+//
+// Only the newline terminating an event is translated, newlines inside field
+// values are escaped in JSON output and left alone.
+func WithLineEnding(ending string) Option {
+	return func(o *options) {
+		o.lineEnding = ending
+	}
+}
+
+// lineEndingWriter replaces the trailing newline of each write with ending.
+type lineEndingWriter struct {
+	out    io.Writer
+	ending []byte
+}
+
+func (w *lineEndingWriter) Write(p []byte) (int, error) {
+	return w.WriteLevel(zerolog.NoLevel, p)
+}
+
+func (w *lineEndingWriter) WriteLevel(level zerolog.Level, p []byte) (int, error) {
+	if !bytes.HasSuffix(p, []byte("\n")) {
+		return writeLevel(w.out, level, p)
+	}
+
+	line := make([]byte, 0, len(p)-1+len(w.ending))
+	line = append(line, p[:len(p)-1]...)
+	line = append(line, w.ending...)
+	if _, err := writeLevel(w.out, level, line); err != nil {
+		return 0, err
+	}
+
+	return len(p), nil
+}
+
+// Flush flushes the underlying writer when it buffers.
+func (w *lineEndingWriter) Flush() error {
+	if f, ok := w.out.(flusher); ok {
+		return f.Flush()
+	}
+
+	return nil
+}
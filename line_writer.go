@@ -0,0 +1,53 @@
+package zerolog_wrapper
+
+import (
+	"io"
+	"sync"
+
+	"github.com/rs/zerolog"
+)
+
+// flusher is implemented by buffering writers such as *bufio.Writer.
+type flusher interface {
+	Flush() error
+}
+
+// lineWriter hands each event to w in a single write, one at a time, and
+// flushes w afterwards when it buffers. This way every event ends up as one
+// complete line, never split or interleaved with another event, even when
+// logging from many goroutines at once.
+type lineWriter struct {
+	mu sync.Mutex
+	w  io.Writer
+}
+
+func newLineWriter(w io.Writer) *lineWriter {
+	return &lineWriter{w: w}
+}
+
+func (lw *lineWriter) Write(p []byte) (int, error) {
+	return lw.WriteLevel(zerolog.NoLevel, p)
+}
+
+func (lw *lineWriter) WriteLevel(level zerolog.Level, p []byte) (int, error) {
+	lw.mu.Lock()
+	defer lw.mu.Unlock()
+
+	var n int
+	var err error
+	if w, ok := lw.w.(zerolog.LevelWriter); ok {
+		n, err = w.WriteLevel(level, p)
+	} else {
+		n, err = lw.w.Write(p)
+	}
+	if err == nil && n < len(p) {
+		err = io.ErrShortWrite
+	}
+	if err == nil {
+		if f, ok := lw.w.(flusher); ok {
+			err = f.Flush()
+		}
+	}
+
+	return n, err
+}
@@ -0,0 +1,85 @@
+package zerolog_wrapper
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"io"
+	"strings"
+	"sync"
+	"testing"
+)
+
+// writeRecorder keeps every write it gets apart. It is deliberately not safe
+// for concurrent use, so overlapping writes show up as corrupted lines.
+type writeRecorder struct {
+	writes [][]byte
+}
+
+func (w *writeRecorder) Write(p []byte) (int, error) {
+	w.writes = append(w.writes, append([]byte(nil), p...))
+	return len(p), nil
+}
+
+func logConcurrently(t *testing.T, w io.Writer, goroutines, events int) {
+	t.Helper()
+
+	l, err := New(InfoLevel, Prod, DisableHostIP(), WithOutput(w))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	payload := strings.Repeat("x", 100)
+	var wg sync.WaitGroup
+	for g := 0; g < goroutines; g++ {
+		wg.Add(1)
+		go func(g int) {
+			defer wg.Done()
+			for n := 0; n < events; n++ {
+				l.Info().Int("goroutine", g).Int("n", n).Str("payload", payload).Msg("concurrent")
+			}
+		}(g)
+	}
+	wg.Wait()
+}
+
+func checkLines(t *testing.T, out []byte, want int) {
+	t.Helper()
+
+	lines := bytes.Split(bytes.TrimSuffix(out, []byte("\n")), []byte("\n"))
+	if len(lines) != want {
+		t.Fatalf("got %d lines, want %d", len(lines), want)
+	}
+	for _, line := range lines {
+		if !json.Valid(line) {
+			t.Fatalf("partial JSON line: %q", line)
+		}
+	}
+}
+
+func TestConcurrentWritesAreCompleteLines(t *testing.T) {
+	w := &writeRecorder{}
+	logConcurrently(t, w, 20, 100)
+
+	if len(w.writes) != 20*100 {
+		t.Fatalf("got %d writes, want one per event", len(w.writes))
+	}
+	for _, p := range w.writes {
+		if !bytes.HasSuffix(p, []byte("\n")) || bytes.Count(p, []byte("\n")) != 1 {
+			t.Fatalf("write is not exactly one line: %q", p)
+		}
+	}
+	checkLines(t, bytes.Join(w.writes, nil), 20*100)
+}
+
+func TestConcurrentWritesFlushBufferedOutput(t *testing.T) {
+	var buf bytes.Buffer
+	// smaller than one event, so every event spans several flushes
+	bw := bufio.NewWriterSize(&buf, 64)
+	logConcurrently(t, bw, 20, 100)
+
+	if bw.Buffered() != 0 {
+		t.Fatalf("%d bytes left in the buffer after Msg", bw.Buffered())
+	}
+	checkLines(t, buf.Bytes(), 20*100)
+}
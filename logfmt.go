@@ -0,0 +1,159 @@
+package zerolog_wrapper
+
+import (
+	"bytes"
+	"io"
+	"strconv"
+	"strings"
+	"sync"
+
+	"github.com/rs/zerolog"
+)
+
+// ParseLogfmt parses a logfmt line such as
+//
+//	level=info msg="user logged in" user=42 admin
+//
+// into its key value pairs. Quoted values may contain spaces and Go escape
+// sequences, keys without a value are set to true. All other values are kept
+// as strings since logfmt carries no types.
+func ParseLogfmt(line string) map[string]interface{} {
+	fields := map[string]interface{}{}
+
+	for i := 0; i < len(line); {
+		// skip separating spaces
+		for i < len(line) && (line[i] == ' ' || line[i] == '\t') {
+			i++
+		}
+		start := i
+		for i < len(line) && line[i] != '=' && line[i] != ' ' && line[i] != '\t' {
+			i++
+		}
+		key := line[start:i]
+		if key == "" {
+			i++
+			continue
+		}
+		if i >= len(line) || line[i] != '=' {
+			fields[key] = true
+			continue
+		}
+		i++ // skip '='
+
+		if i < len(line) && line[i] == '"' {
+			start = i
+			for i++; i < len(line) && line[i] != '"'; i++ {
+				if line[i] == '\\' {
+					i++
+				}
+			}
+			if i < len(line) {
+				i++ // include the closing quote
+			}
+			fields[key] = unquoteLogfmt(line[start:i])
+			continue
+		}
+
+		start = i
+		for i < len(line) && line[i] != ' ' && line[i] != '\t' {
+			i++
+		}
+		fields[key] = line[start:i]
+	}
+
+	return fields
+}
+
+// unquoteLogfmt unquotes a quoted logfmt value, falling back to the raw
+// content between the quotes when it holds invalid escape sequences.
+func unquoteLogfmt(quoted string) string {
+	if value, err := strconv.Unquote(quoted); err == nil {
+		return value
+	}
+
+	value := strings.TrimPrefix(quoted, `"`)
+	return strings.TrimSuffix(value, `"`)
+}
+
+// logfmtWriter re-emits logfmt lines written to it as events of a logger.
+type logfmtWriter struct {
+	mu      sync.Mutex
+	logger  zerolog.Logger
+	partial []byte
+}
+
+// NewLogfmtWriter returns a writer which parses every line written to it as
+// logfmt and logs it through l, so the output of eg: a subprocess ends up as
+// JSON events. The level and msg (or message) keys become the level and
+// message of the event, all other keys become fields. The message defaults
+// to empty and the level to info, which is also used for levels other than
+// trace to panic. Close logs a last line which lacks its newline.
+//
+// eg:
+//
+//	w := log.NewLogfmtWriter(log.GetLogger())
+//	cmd.Stdout = w
+//	err := cmd.Run()
+//	w.Close()
+func NewLogfmtWriter(l zerolog.Logger) io.WriteCloser {
+	return &logfmtWriter{logger: l}
+}
+
+func (w *logfmtWriter) Write(p []byte) (int, error) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	w.partial = append(w.partial, p...)
+	for {
+		i := bytes.IndexByte(w.partial, '\n')
+		if i < 0 {
+			break
+		}
+		line := strings.TrimSuffix(string(w.partial[:i]), "\r")
+		w.partial = w.partial[i+1:]
+		if strings.TrimSpace(line) != "" {
+			w.emit(ParseLogfmt(line))
+		}
+	}
+
+	return len(p), nil
+}
+
+// Close logs the line written last when it lacks its newline.
+func (w *logfmtWriter) Close() error {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	line := strings.TrimSuffix(string(w.partial), "\r")
+	w.partial = nil
+	if strings.TrimSpace(line) != "" {
+		w.emit(ParseLogfmt(line))
+	}
+
+	return nil
+}
+
+// emit logs the parsed fields of a single line.
+func (w *logfmtWriter) emit(fields map[string]interface{}) {
+	level := zerolog.InfoLevel
+	if s, ok := fields["level"].(string); ok {
+		// zerolog also parses disabled and numeric levels, which aren't levels of an event
+		name := strings.ToLower(s)
+		parsed, err := zerolog.ParseLevel(name)
+		if err == nil && parsed >= zerolog.TraceLevel && parsed <= zerolog.PanicLevel && parsed.String() == name {
+			level = parsed
+		}
+	}
+	delete(fields, "level")
+
+	var msg string
+	for _, key := range []string{"msg", "message"} {
+		if s, ok := fields[key].(string); ok {
+			msg = s
+			delete(fields, key)
+			break
+		}
+	}
+
+	w.logger.WithLevel(level).Fields(fields).Msg(msg)
+}
@@ -0,0 +1,55 @@
+package zerolog_wrapper
+
+import (
+	"bytes"
+	"encoding/json"
+	"testing"
+
+	"github.com/rs/zerolog"
+)
+
+func TestLogfmtWriter(t *testing.T) {
+	var buf bytes.Buffer
+	w := NewLogfmtWriter(zerolog.New(&buf))
+
+	input := "level=warn msg=\"disk low\" free=10\r\n" +
+		"level=disabled msg=hidden\n" +
+		"level=3 msg=numeric\n" +
+		"level=ERROR msg=upper\n" +
+		"\n" +
+		"msg=\"no newline\" last"
+	// written in two parts, lines are put back together
+	if _, err := w.Write([]byte(input[:20])); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := w.Write([]byte(input[20:])); err != nil {
+		t.Fatal(err)
+	}
+	if err := w.Close(); err != nil {
+		t.Fatal(err)
+	}
+
+	want := []struct{ level, message string }{
+		{"warn", "disk low"},
+		{"info", "hidden"},
+		{"info", "numeric"},
+		{"error", "upper"},
+		{"info", "no newline"},
+	}
+	lines := bytes.Split(bytes.TrimSpace(buf.Bytes()), []byte("\n"))
+	if len(lines) != len(want) {
+		t.Fatalf("got %d events, want %d: %s", len(lines), len(want), buf.String())
+	}
+	for i, w := range want {
+		var event map[string]interface{}
+		if err := json.Unmarshal(lines[i], &event); err != nil {
+			t.Fatal(err)
+		}
+		if event["level"] != w.level || event["message"] != w.message {
+			t.Errorf("event %d = %v, want %s %q", i, event, w.level, w.message)
+		}
+	}
+	if !bytes.Contains(lines[0], []byte(`"free":"10"`)) || !bytes.Contains(lines[4], []byte(`"last":true`)) {
+		t.Errorf("fields missing: %s", buf.String())
+	}
+}
@@ -0,0 +1,139 @@
+// Package logrsink provides a go-logr/logr sink writing through zerolog_wrapper,
+// so the logs of Kubernetes libraries such as client-go and controller-runtime
+// share the output, format and level of the wrapper.
+//
+// It is kept apart from zerolog_wrapper so the main package doesn't depend on logr.
+//
+//	log.InitLog(log.InfoLevel, "prod")
+//	ctrl.SetLogger(logrsink.New())
+package logrsink
+
+import (
+	"fmt"
+
+	zerolog_wrapper "github.com/ashokrajar/zerolog_wrapper"
+	"github.com/go-logr/logr"
+	"github.com/rs/zerolog"
+)
+
+// sink is a logr.LogSink writing zerolog events.
+type sink struct {
+	logger    func() zerolog.Logger
+	name      string
+	values    []interface{}
+	callDepth int
+}
+
+// sinkFrames is the number of frames of the sink between logr and the
+// event's Msg, ie: Info or Error then write.
+const sinkFrames = 2
+
+// New returns a logr.Logger writing through the global logger.
+func New() logr.Logger {
+	return logr.New(&sink{logger: zerolog_wrapper.GetLogger})
+}
+
+// NewForInstance returns a logr.Logger writing through i.
+func NewForInstance(i *zerolog_wrapper.Instance) logr.Logger {
+	return logr.New(&sink{logger: i.GetLogger})
+}
+
+// Level maps a logr V-level to the wrapper's levels: V(0) is info, V(1)
+// debug and anything more verbose trace.
+func Level(v int) zerolog.Level {
+	switch {
+	case v <= 0:
+		return zerolog.InfoLevel
+	case v == 1:
+		return zerolog.DebugLevel
+	default:
+		return zerolog.TraceLevel
+	}
+}
+
+// Init records the frames logr adds above the sink, so the caller field
+// added by the wrapper's logger is the code calling logr.
+func (s *sink) Init(info logr.RuntimeInfo) {
+	s.callDepth = info.CallDepth
+}
+
+func (s *sink) Enabled(level int) bool {
+	l := s.logger()
+	zl := Level(level)
+
+	return zl >= l.GetLevel() && zl >= zerolog.GlobalLevel()
+}
+
+func (s *sink) Info(level int, msg string, keysAndValues ...interface{}) {
+	l := s.logger()
+	s.write(l.WithLevel(Level(level)), msg, keysAndValues)
+}
+
+func (s *sink) Error(err error, msg string, keysAndValues ...interface{}) {
+	l := s.logger()
+	s.write(l.Error().Err(err), msg, keysAndValues)
+}
+
+func (s *sink) WithValues(keysAndValues ...interface{}) logr.LogSink {
+	c := *s
+	c.values = append(append([]interface{}(nil), s.values...), keysAndValues...)
+
+	return &c
+}
+
+// WithCallDepth implements logr.CallDepthLogSink, depth frames more are
+// skipped for the caller field.
+func (s *sink) WithCallDepth(depth int) logr.LogSink {
+	c := *s
+	c.callDepth += depth
+
+	return &c
+}
+
+// WithName appends name to the logger field, joined by a slash as logr suggests.
+func (s *sink) WithName(name string) logr.LogSink {
+	c := *s
+	if c.name != "" {
+		c.name += "/"
+	}
+	c.name += name
+
+	return &c
+}
+
+// write adds the logger name and the key value pairs to e and sends it.
+func (s *sink) write(e *zerolog.Event, msg string, keysAndValues []interface{}) {
+	if e == nil {
+		return
+	}
+
+	if s.name != "" {
+		e.Str("logger", s.name)
+	}
+	appendValues(e, s.values)
+	appendValues(e, keysAndValues)
+	e.CallerSkipFrame(sinkFrames + s.callDepth).Msg(msg)
+}
+
+// appendValues adds keysAndValues as fields of e. Keys which aren't strings
+// are formatted with fmt, a key without value gets "<no-value>".
+func appendValues(e *zerolog.Event, keysAndValues []interface{}) {
+	for i := 0; i < len(keysAndValues); i += 2 {
+		key, ok := keysAndValues[i].(string)
+		if !ok {
+			key = fmt.Sprint(keysAndValues[i])
+		}
+
+		if i+1 == len(keysAndValues) {
+			e.Str(key, "<no-value>")
+			break
+		}
+
+		switch v := keysAndValues[i+1].(type) {
+		case error:
+			e.AnErr(key, v)
+		default:
+			e.Interface(key, v)
+		}
+	}
+}
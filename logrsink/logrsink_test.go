@@ -0,0 +1,124 @@
+package logrsink
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"strings"
+	"testing"
+
+	zerolog_wrapper "github.com/ashokrajar/zerolog_wrapper"
+	"github.com/go-logr/logr"
+	"github.com/rs/zerolog"
+)
+
+func newLogger(t *testing.T, level zerolog_wrapper.LogLevel) (logr.Logger, *bytes.Buffer) {
+	t.Helper()
+
+	var buf bytes.Buffer
+	i, err := zerolog_wrapper.New(level, zerolog_wrapper.Prod,
+		zerolog_wrapper.DisableHostIP(), zerolog_wrapper.WithOutput(&buf))
+	if err != nil {
+		t.Fatal(err)
+	}
+	return NewForInstance(i), &buf
+}
+
+func events(t *testing.T, buf *bytes.Buffer) []map[string]interface{} {
+	t.Helper()
+
+	var events []map[string]interface{}
+	for _, line := range bytes.Split(bytes.TrimSpace(buf.Bytes()), []byte("\n")) {
+		if len(line) == 0 {
+			continue
+		}
+		var event map[string]interface{}
+		if err := json.Unmarshal(line, &event); err != nil {
+			t.Fatalf("%v: %s", err, line)
+		}
+		events = append(events, event)
+	}
+	return events
+}
+
+func TestLevel(t *testing.T) {
+	tests := []struct {
+		v    int
+		want zerolog.Level
+	}{
+		{-1, zerolog.InfoLevel},
+		{0, zerolog.InfoLevel},
+		{1, zerolog.DebugLevel},
+		{2, zerolog.TraceLevel},
+		{10, zerolog.TraceLevel},
+	}
+
+	for _, tt := range tests {
+		if got := Level(tt.v); got != tt.want {
+			t.Errorf("Level(%d) = %s, want %s", tt.v, got, tt.want)
+		}
+	}
+}
+
+func TestVLevels(t *testing.T) {
+	logger, buf := newLogger(t, zerolog_wrapper.DebugLevel)
+
+	logger.Info("info")
+	logger.V(1).Info("debug")
+	logger.V(2).Info("trace")
+	logger.Error(errors.New("boom"), "error")
+
+	got := events(t, buf)
+	if len(got) != 3 {
+		t.Fatalf("got %d events, want 3: %s", len(got), buf)
+	}
+	for i, want := range []string{"info", "debug", "error"} {
+		if got[i]["level"] != want || got[i]["message"] != want {
+			t.Errorf("event %d = %v, want level and message %q", i, got[i], want)
+		}
+	}
+	if got[2]["error"] != "boom" {
+		t.Errorf("error = %v, want boom", got[2]["error"])
+	}
+}
+
+func TestWithNameAndValues(t *testing.T) {
+	logger, buf := newLogger(t, zerolog_wrapper.InfoLevel)
+
+	logger.WithName("a").WithName("b").WithValues("k", "v").Info("msg", "n", 1)
+
+	got := events(t, buf)[0]
+	if got["logger"] != "a/b" || got["k"] != "v" || got["n"] != float64(1) {
+		t.Errorf("unexpected event: %v", got)
+	}
+}
+
+func TestOddKeysAndValues(t *testing.T) {
+	logger, buf := newLogger(t, zerolog_wrapper.InfoLevel)
+
+	logger.Info("msg", "k", "v", 3, "three", "dangling")
+
+	got := events(t, buf)[0]
+	if got["k"] != "v" || got["3"] != "three" || got["dangling"] != "<no-value>" {
+		t.Errorf("unexpected event: %v", got)
+	}
+}
+
+func logThroughHelper(logger logr.Logger) {
+	logger.WithCallDepth(1).Info("through helper")
+}
+
+func TestCaller(t *testing.T) {
+	logger, buf := newLogger(t, zerolog_wrapper.TraceLevel)
+
+	logger.Info("info")
+	logger.Error(errors.New("boom"), "error")
+	logThroughHelper(logger)
+
+	for _, event := range events(t, buf) {
+		caller, _ := event[zerolog.CallerFieldName].(string)
+		if !strings.Contains(caller, "logrsink_test.go") {
+			t.Errorf("%v: caller = %q, want the logr call site", event["message"], caller)
+		}
+	}
+}
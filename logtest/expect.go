@@ -0,0 +1,197 @@
+package logtest
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"strings"
+	"sync"
+	"testing"
+
+	zerolog_wrapper "github.com/ashokrajar/zerolog_wrapper"
+	"github.com/rs/zerolog"
+)
+
+// Entry is a single captured event, decoded from JSON.
+type Entry map[string]interface{}
+
+// Recorder captures JSON events written to it, eg: as the output of the
+// global logger:
+//
+//	rec := logtest.NewRecorder()
+//	logger, _ := log.New(log.TraceLevel, "prod", log.WithOutput(rec))
+type Recorder struct {
+	mu      sync.Mutex
+	entries []Entry
+}
+
+// NewRecorder returns an empty Recorder.
+func NewRecorder() *Recorder {
+	return &Recorder{}
+}
+
+// Write captures the event p, anything not decoding as JSON is kept under message.
+func (r *Recorder) Write(p []byte) (int, error) {
+	entry := Entry{}
+	if err := json.Unmarshal(p, &entry); err != nil {
+		entry = Entry{zerolog.MessageFieldName: string(bytes.TrimSuffix(p, []byte("\n")))}
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.entries = append(r.entries, entry)
+
+	return len(p), nil
+}
+
+// Logger returns a logger writing to r.
+func (r *Recorder) Logger() zerolog.Logger {
+	return zerolog.New(r).Level(zerolog.TraceLevel)
+}
+
+// Entries returns the captured events, oldest first.
+func (r *Recorder) Entries() []Entry {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	return append([]Entry(nil), r.entries...)
+}
+
+// Reset drops the captured events.
+func (r *Recorder) Reset() {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.entries = nil
+}
+
+// matcher checks a single field of an entry.
+type matcher struct {
+	key   string
+	value interface{}
+}
+
+func (m matcher) String() string {
+	return fmt.Sprintf("%s=%v", m.key, m.value)
+}
+
+// Expectation describes the events expected in a Recorder, see ExpectLog.
+type Expectation struct {
+	t        testing.TB
+	rec      *Recorder
+	matchers []matcher
+}
+
+// ExpectLog starts an expectation on the events captured by rec, narrowed
+// down by Level, Message and Field and checked by Once, Times or Never.
+//
+// eg:
+//
+//	logtest.ExpectLog(t, rec).Level(log.ErrorLevel).Message("db failed").Field("code", 500).Once()
+//
+// A failed check reports the expectation and every captured event with the
+// fields it didn't match.
+func ExpectLog(t testing.TB, rec *Recorder) *Expectation {
+	return &Expectation{t: t, rec: rec}
+}
+
+// Level expects events of level.
+func (x *Expectation) Level(level zerolog_wrapper.LogLevel) *Expectation {
+	return x.Field(zerolog.LevelFieldName, string(level))
+}
+
+// Message expects events with the message msg.
+func (x *Expectation) Message(msg string) *Expectation {
+	return x.Field(zerolog.MessageFieldName, msg)
+}
+
+// Field expects events whose field key equals value once both are
+// serialized as JSON, so eg: an int matches the float64 decoded from JSON.
+func (x *Expectation) Field(key string, value interface{}) *Expectation {
+	x.matchers = append(x.matchers, matcher{key: key, value: normalize(value)})
+	return x
+}
+
+// Once asserts that exactly one captured event matches.
+func (x *Expectation) Once() {
+	x.t.Helper()
+	x.Times(1)
+}
+
+// Never asserts that no captured event matches.
+func (x *Expectation) Never() {
+	x.t.Helper()
+	x.Times(0)
+}
+
+// Times asserts that exactly n captured events match.
+func (x *Expectation) Times(n int) {
+	x.t.Helper()
+
+	entries := x.rec.Entries()
+	var report strings.Builder
+	found := 0
+	for i, entry := range entries {
+		mismatched := x.mismatches(entry)
+		if len(mismatched) == 0 {
+			found++
+			fmt.Fprintf(&report, "\n  #%d matched: %s", i, encode(entry))
+			continue
+		}
+		fmt.Fprintf(&report, "\n  #%d mismatched %s: %s", i, strings.Join(mismatched, ", "), encode(entry))
+	}
+
+	if found != n {
+		x.t.Errorf("expected %d log entries matching %s, found %d in %d captured entries:%s",
+			n, x.describe(), found, len(entries), report.String())
+	}
+}
+
+// mismatches returns the description of each matcher entry fails.
+func (x *Expectation) mismatches(entry Entry) []string {
+	var mismatched []string
+	for _, m := range x.matchers {
+		got, ok := entry[m.key]
+		switch {
+		case !ok:
+			mismatched = append(mismatched, m.key+" (missing)")
+		case !reflect.DeepEqual(got, m.value):
+			mismatched = append(mismatched, fmt.Sprintf("%s (got %v)", m.key, got))
+		}
+	}
+
+	return mismatched
+}
+
+func (x *Expectation) describe() string {
+	if len(x.matchers) == 0 {
+		return "anything"
+	}
+
+	parts := make([]string, len(x.matchers))
+	for i, m := range x.matchers {
+		parts[i] = m.String()
+	}
+
+	return strings.Join(parts, " ")
+}
+
+// normalize returns value as it would be decoded from JSON.
+func normalize(value interface{}) interface{} {
+	b, err := json.Marshal(value)
+	if err != nil {
+		return value
+	}
+
+	var normalized interface{}
+	if err := json.Unmarshal(b, &normalized); err != nil {
+		return value
+	}
+
+	return normalized
+}
+
+func encode(entry Entry) string {
+	b, _ := json.Marshal(entry)
+	return string(b)
+}
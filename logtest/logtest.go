@@ -0,0 +1,39 @@
+// Package logtest provides helpers for using zerolog loggers in tests.
+//
+// It is kept apart from zerolog_wrapper so the main package doesn't import testing.
+//
+//	func TestSomething(t *testing.T) {
+//	    logger := logtest.TestLogger(t)
+//	    logger.Info().Msg("shows up in the output of TestSomething")
+//	}
+package logtest
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/rs/zerolog"
+)
+
+// testWriter writes each event through t.Log.
+type testWriter struct {
+	t testing.TB
+}
+
+func (w testWriter) Write(p []byte) (int, error) {
+	w.t.Helper()
+	w.t.Log(strings.TrimSuffix(string(p), "\n"))
+
+	return len(p), nil
+}
+
+// TestLogger returns a logger writing every event through t.Log, so log output
+// is attributed to the test that produced it, shown inline when it fails and
+// otherwise controlled by go test -v.
+func TestLogger(t testing.TB) zerolog.Logger {
+	return zerolog.New(testWriter{t: t}).
+		Level(zerolog.TraceLevel).
+		With().
+		Timestamp().
+		Logger()
+}
@@ -0,0 +1,45 @@
+package zerolog_wrapper
+
+import "github.com/rs/zerolog"
+
+// WithMaxFields caps the number of fields of a single event at max, guarding
+// against loops adding fields without bound. Fields beyond the cap are
+// dropped and the event is marked with "fields_truncated":true. The time,
+// level, message and caller fields are always kept and don't count towards max.
+//
+// Without this option, or with a zero or negative max, the number of fields is unlimited.
+func WithMaxFields(max int) Option {
+	return func(o *options) {
+		if max <= 0 {
+			return
+		}
+		o.transforms = append(o.transforms, maxFields(max))
+	}
+}
+
+func maxFields(max int) fieldTransform {
+	return func(fields []jsonField) []jsonField {
+		kept := fields[:0]
+		count := 0
+		truncated := false
+		for _, field := range fields {
+			switch field.key {
+			case zerolog.TimestampFieldName, zerolog.LevelFieldName, zerolog.MessageFieldName, zerolog.CallerFieldName:
+				kept = append(kept, field)
+				continue
+			}
+			if count >= max {
+				truncated = true
+				continue
+			}
+			count++
+			kept = append(kept, field)
+		}
+
+		if truncated {
+			kept = append(kept, jsonField{key: "fields_truncated", value: []byte("true")})
+		}
+
+		return kept
+	}
+}
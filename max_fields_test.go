@@ -0,0 +1,34 @@
+package zerolog_wrapper
+
+import (
+	"bytes"
+	"testing"
+)
+
+func TestWithMaxFields(t *testing.T) {
+	for _, tt := range []struct {
+		max       int
+		truncated bool
+	}{
+		{2, true},
+		{3, false},
+		// no cap rather than dropping every field
+		{0, false},
+		{-1, false},
+	} {
+		var buf bytes.Buffer
+		l, err := New(InfoLevel, Prod, DisableHostIP(), WithOutput(&buf), WithMaxFields(tt.max))
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		l.Info().Int("a", 1).Int("b", 2).Int("c", 3).Msg("fields")
+
+		if got := bytes.Contains(buf.Bytes(), []byte(`"fields_truncated":true`)); got != tt.truncated {
+			t.Errorf("max %d: truncated = %v, want %v: %s", tt.max, got, tt.truncated, buf.String())
+		}
+		if !tt.truncated && !bytes.Contains(buf.Bytes(), []byte(`"c":3`)) {
+			t.Errorf("max %d: field dropped: %s", tt.max, buf.String())
+		}
+	}
+}
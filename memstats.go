@@ -0,0 +1,30 @@
+package zerolog_wrapper
+
+import (
+	"runtime"
+	"time"
+)
+
+// LogMemStats logs a snapshot of the runtime memory statistics at debug level.
+func LogMemStats() {
+	var m runtime.MemStats
+	runtime.ReadMemStats(&m)
+
+	Debug().
+		Uint64("alloc", m.Alloc).
+		Uint64("heap_inuse", m.HeapInuse).
+		Uint32("num_gc", m.NumGC).
+		Int("goroutines", runtime.NumGoroutine()).
+		Msg("memory stats")
+}
+
+// StartMemStatsReporter calls LogMemStats every interval until the returned
+// function is called. A zero or negative interval reports nothing.
+//
+// eg:
+//
+//	stop := log.StartMemStatsReporter(time.Minute)
+//	defer stop()
+func StartMemStatsReporter(interval time.Duration) (stop func()) {
+	return every(interval, LogMemStats)
+}
@@ -0,0 +1,19 @@
+package zerolog_wrapper
+
+// Metric logs a metric value at info level for log based metrics pipelines.
+// The schema of the event is stable:
+//
+//	{"level":"info","metric_name":"queue_depth","metric_value":42,"tags":{"queue":"emails"},"message":"metric"}
+//
+// tags is left out when there are no tags.
+func Metric(name string, value float64, tags map[string]string) {
+	e := Info().
+		Str("metric_name", name).
+		Float64("metric_value", value)
+
+	if len(tags) > 0 {
+		e = e.Dict("tags", stringDict(tags))
+	}
+
+	e.Msg("metric")
+}
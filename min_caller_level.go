@@ -0,0 +1,28 @@
+package zerolog_wrapper
+
+import "github.com/rs/zerolog"
+
+// callerHookSkipFrames are the frames between the caller of Msg and Event.Caller called from a hook.
+const callerHookSkipFrames = 3
+
+// WithMinCallerLevel adds the caller field only to events of level or
+// higher, eg: to keep the provenance of warnings and errors without paying
+// for it on every debug line. It replaces the caller added to all events in
+// the dev environment and at trace level, and adds the caller in the other
+// environments as well.
+func WithMinCallerLevel(level LogLevel) Option {
+	return func(o *options) {
+		l := toZerologLevel(level)
+		o.minCallerLevel = &l
+	}
+}
+
+type minCallerLevelHook struct {
+	level zerolog.Level
+}
+
+func (h minCallerLevelHook) Run(e *zerolog.Event, level zerolog.Level, msg string) {
+	if level >= h.level && level <= zerolog.PanicLevel {
+		e.Caller(callerHookSkipFrames)
+	}
+}
@@ -0,0 +1,37 @@
+package zerolog_wrapper
+
+import "bytes"
+
+// WithOmitEmpty drops top level fields whose value is null, an empty string,
+// an empty array or an empty object before the event is written, eg: an
+// Interface field holding a nil map. Zero numbers and false are kept.
+func WithOmitEmpty() Option {
+	return func(o *options) {
+		o.transforms = append(o.transforms, omitEmpty)
+	}
+}
+
+// emptyValues are the serialized values dropped by omitEmpty.
+var emptyValues = [][]byte{[]byte("null"), []byte(`""`), []byte("[]"), []byte("{}")}
+
+func omitEmpty(fields []jsonField) []jsonField {
+	kept := fields[:0]
+	for _, field := range fields {
+		if !isEmptyValue(field.value) {
+			kept = append(kept, field)
+		}
+	}
+
+	return kept
+}
+
+func isEmptyValue(value []byte) bool {
+	value = bytes.TrimSpace(value)
+	for _, empty := range emptyValues {
+		if bytes.Equal(value, empty) {
+			return true
+		}
+	}
+
+	return false
+}
@@ -0,0 +1,115 @@
+package zerolog_wrapper
+
+import (
+	"compress/gzip"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"time"
+
+	"github.com/rs/zerolog"
+)
+
+// Option configures optional behaviour of the global logger set up by InitLog, or of an Instance.
+type Option func(*options)
+
+type options struct {
+	output          io.Writer
+	transforms      []fieldTransform
+	quietBufferSize int
+	levelNames      map[zerolog.Level]string
+	lineEnding      string
+	hooks           []zerolog.Hook
+	recentLogsSize  int
+	cloudProvider   CloudProvider
+	bufferSize      int
+	flushInterval   time.Duration
+	asyncQueueSize  int
+	sampling        *SamplerConfig
+	onSampledOut    func(level LogLevel, msg string)
+	disableHostIP   bool
+	gzipLevel       *int
+	schemaVersion   *int
+	outputSpecs     []OutputSpec
+	k8sEnv          map[string]string
+	minCallerLevel  *zerolog.Level
+	filePath        string
+	file            *os.File
+	// skipCallbacks leaves out the filters and dynamic fields registered by the application
+	skipCallbacks bool
+	// fatalFlushTimeout is defaultFatalFlushTimeout unless set by WithFatalFlushTimeout
+	fatalFlushTimeout time.Duration
+}
+
+func newOptions(opts []Option) *options {
+	o := &options{fatalFlushTimeout: defaultFatalFlushTimeout}
+	for _, opt := range opts {
+		opt(o)
+	}
+
+	return o
+}
+
+// validate reports options which can't be applied.
+func (o *options) validate() error {
+	switch {
+	case o.quietBufferSize < 0:
+		return errors.New("negative quiet buffer size")
+	case o.recentLogsSize < 0:
+		return errors.New("negative recent logs size")
+	case o.bufferSize < 0:
+		return errors.New("negative buffer size")
+	case o.flushInterval < 0:
+		return errors.New("negative flush interval")
+	case o.asyncQueueSize < 0:
+		return errors.New("negative async queue size")
+	case o.fatalFlushTimeout < 0:
+		return errors.New("negative fatal flush timeout")
+	}
+
+	if o.gzipLevel != nil && (*o.gzipLevel < gzip.HuffmanOnly || *o.gzipLevel > gzip.BestCompression) {
+		return fmt.Errorf("invalid gzip level %d", *o.gzipLevel)
+	}
+
+	for _, hook := range o.hooks {
+		if h, ok := hook.(preExitHook); ok {
+			switch {
+			case h.fn == nil:
+				return errors.New("pre-exit hook without function")
+			case h.timeout <= 0:
+				return fmt.Errorf("non-positive pre-exit timeout %s", h.timeout)
+			}
+		}
+	}
+
+	for _, spec := range o.outputSpecs {
+		if spec.Writer == nil {
+			return errors.New("output spec without writer")
+		}
+		switch spec.Format {
+		case "", JSONFormat, ConsoleFormat, ECSFormat, GCPFormat:
+		default:
+			return fmt.Errorf("unknown output format %q", spec.Format)
+		}
+	}
+
+	switch o.cloudProvider {
+	case "", CloudNone, CloudAWS, CloudGCP:
+	default:
+		return fmt.Errorf("unknown cloud provider %q", o.cloudProvider)
+	}
+
+	return nil
+}
+
+// WithOutput replaces the default destination (stderr, or the console writer in
+// dev) with w. Writers implementing zerolog.LevelWriter receive the level of each
+// event and writers with a Flush() error method are flushed after every event.
+// Writers with a Flush(context.Context) error method, like stream.Writer, are
+// flushed by Flush, Shutdown, Critical and before a fatal event exits instead.
+func WithOutput(w io.Writer) Option {
+	return func(o *options) {
+		o.output = w
+	}
+}
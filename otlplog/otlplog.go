@@ -0,0 +1,326 @@
+// Package otlplog exports log events to an OpenTelemetry collector over the
+// OTLP/HTTP logs protocol, using its JSON encoding.
+//
+// It is kept apart from zerolog_wrapper and speaks the protocol directly, so
+// neither package pulls in the OpenTelemetry SDK:
+//
+//	w := otlplog.NewWriter(otlplog.Config{Endpoint: "http://otel-collector:4318", ServiceName: "billing"})
+//	defer w.Close()
+//	log.InitLog(log.InfoLevel, "prod", log.WithOutput(w))
+package otlplog
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+
+	zerolog_wrapper "github.com/ashokrajar/zerolog_wrapper"
+	"github.com/ashokrajar/zerolog_wrapper/stream"
+	"github.com/rs/zerolog"
+)
+
+// Config configures the export.
+type Config struct {
+	// Endpoint is the base URL of the collector, eg: http://localhost:4318.
+	// The logs are posted to its /v1/logs path.
+	Endpoint string
+	// ServiceName is exported as the service.name resource attribute.
+	ServiceName string
+	// ResourceAttributes are exported as additional resource attributes.
+	ResourceAttributes map[string]string
+	// Headers are sent with every request, eg: for authentication.
+	Headers map[string]string
+	// Client sends the requests, http.DefaultClient by default.
+	Client *http.Client
+	// MaxRetries bounds the retries of a failed export, 3 by default. Only
+	// network errors and the retryable status codes of the OTLP specification
+	// (429, 502, 503 and 504) are retried, with exponential backoff.
+	MaxRetries int
+	// RetryBackoff is the wait before the first retry, doubled for every
+	// following one, one second by default.
+	RetryBackoff time.Duration
+}
+
+// NewWriter returns a writer exporting each JSON event written to it as an
+// OTLP log record. Events are exported in batches from a background
+// goroutine, see stream.NewWriter for opts. When the export fails for good
+// the events are written to the fallback of the writer, os.Stderr by default.
+//
+// Call Close before the program exits to export the events still queued.
+func NewWriter(cfg Config, opts ...stream.Option) *stream.Writer {
+	// OTLP has no partition key
+	opts = append([]stream.Option{stream.WithKeyField("")}, opts...)
+
+	return stream.NewWriter(NewExporter(cfg), opts...)
+}
+
+// Exporter is a stream.Publisher exporting batches of events to a collector.
+type Exporter struct {
+	cfg      Config
+	url      string
+	resource []keyValue
+}
+
+// NewExporter returns an Exporter for cfg.
+func NewExporter(cfg Config) *Exporter {
+	if cfg.Client == nil {
+		cfg.Client = http.DefaultClient
+	}
+	if cfg.MaxRetries == 0 {
+		cfg.MaxRetries = 3
+	}
+	if cfg.RetryBackoff == 0 {
+		cfg.RetryBackoff = time.Second
+	}
+
+	resource := []keyValue{}
+	if cfg.ServiceName != "" {
+		resource = append(resource, stringAttribute("service.name", cfg.ServiceName))
+	}
+	if host, err := os.Hostname(); err == nil {
+		resource = append(resource, stringAttribute("host.name", host))
+	}
+	for k, v := range cfg.ResourceAttributes {
+		resource = append(resource, stringAttribute(k, v))
+	}
+
+	return &Exporter{
+		cfg:      cfg,
+		url:      strings.TrimSuffix(cfg.Endpoint, "/") + "/v1/logs",
+		resource: resource,
+	}
+}
+
+// Publish exports batch, retrying as the OTLP specification allows.
+func (x *Exporter) Publish(ctx context.Context, batch []stream.Message) error {
+	records := make([]logRecord, 0, len(batch))
+	for _, m := range batch {
+		records = append(records, toLogRecord(m.Value))
+	}
+
+	body, err := json.Marshal(exportRequest{ResourceLogs: []resourceLogs{{
+		Resource:  resource{Attributes: x.resource},
+		ScopeLogs: []scopeLogs{{Scope: scope{Name: "github.com/ashokrajar/zerolog_wrapper"}, LogRecords: records}},
+	}}})
+	if err != nil {
+		return err
+	}
+
+	backoff := x.cfg.RetryBackoff
+	for attempt := 0; ; attempt++ {
+		retry, err := x.post(ctx, body)
+		if err == nil || !retry || attempt >= x.cfg.MaxRetries {
+			return err
+		}
+
+		select {
+		case <-time.After(backoff):
+		case <-ctx.Done():
+			return err
+		}
+		backoff *= 2
+	}
+}
+
+// post sends a single export request, reporting whether a failure may be retried.
+func (x *Exporter) post(ctx context.Context, body []byte) (retry bool, err error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, x.url, bytes.NewReader(body))
+	if err != nil {
+		return false, err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	for k, v := range x.cfg.Headers {
+		req.Header.Set(k, v)
+	}
+
+	resp, err := x.cfg.Client.Do(req)
+	if err != nil {
+		return true, err
+	}
+	defer resp.Body.Close()
+	_, _ = io.Copy(io.Discard, io.LimitReader(resp.Body, 4096))
+
+	switch resp.StatusCode {
+	case http.StatusOK, http.StatusAccepted:
+		return false, nil
+	case http.StatusTooManyRequests, http.StatusBadGateway, http.StatusServiceUnavailable, http.StatusGatewayTimeout:
+		return true, errors.New("otlp export: collector returned " + resp.Status)
+	default:
+		return false, errors.New("otlp export: collector returned " + resp.Status)
+	}
+}
+
+// toLogRecord maps the JSON event p onto a log record. The time, level and
+// message fields become the timestamp, severity and body, every other field
+// an attribute.
+func toLogRecord(p []byte) logRecord {
+	now := strconv.FormatInt(time.Now().UnixNano(), 10)
+	record := logRecord{ObservedTimeUnixNano: now}
+
+	dec := json.NewDecoder(bytes.NewReader(p))
+	dec.UseNumber()
+	var fields map[string]interface{}
+	if err := dec.Decode(&fields); err != nil {
+		record.TimeUnixNano = now
+		record.Body = &anyValue{StringValue: strPtr(strings.TrimSpace(string(p)))}
+		return record
+	}
+
+	record.TimeUnixNano = now
+	if t, ok := fields[zerolog.TimestampFieldName]; ok {
+		if ts, ok := parseTime(t); ok {
+			record.TimeUnixNano = strconv.FormatInt(ts.UnixNano(), 10)
+		}
+		delete(fields, zerolog.TimestampFieldName)
+	}
+	if level, ok := fields[zerolog.LevelFieldName].(string); ok {
+		record.SeverityText = level
+		if l, err := zerolog.ParseLevel(level); err == nil {
+			record.SeverityNumber = zerolog_wrapper.OTelSeverityNumbers[l]
+		}
+		delete(fields, zerolog.LevelFieldName)
+	}
+	if msg, ok := fields[zerolog.MessageFieldName].(string); ok {
+		record.Body = &anyValue{StringValue: strPtr(msg)}
+		delete(fields, zerolog.MessageFieldName)
+	}
+
+	for k, v := range fields {
+		record.Attributes = append(record.Attributes, keyValue{Key: k, Value: toAnyValue(v)})
+	}
+
+	return record
+}
+
+// parseTime reads a time field written with zerolog.TimeFieldFormat.
+func parseTime(v interface{}) (time.Time, bool) {
+	switch t := v.(type) {
+	case string:
+		ts, err := time.Parse(zerolog.TimeFieldFormat, t)
+		if err != nil {
+			ts, err = time.Parse(time.RFC3339Nano, t)
+		}
+		return ts, err == nil
+	case json.Number:
+		n, err := t.Int64()
+		if err != nil {
+			return time.Time{}, false
+		}
+		switch zerolog.TimeFieldFormat {
+		case zerolog.TimeFormatUnixMs:
+			return time.UnixMilli(n), true
+		case zerolog.TimeFormatUnixMicro:
+			return time.UnixMicro(n), true
+		case zerolog.TimeFormatUnixNano:
+			return time.Unix(0, n), true
+		default:
+			return time.Unix(n, 0), true
+		}
+	}
+
+	return time.Time{}, false
+}
+
+// toAnyValue maps a decoded JSON value onto an OTLP AnyValue.
+func toAnyValue(v interface{}) anyValue {
+	switch v := v.(type) {
+	case string:
+		return anyValue{StringValue: strPtr(v)}
+	case bool:
+		return anyValue{BoolValue: &v}
+	case json.Number:
+		if n, err := v.Int64(); err == nil {
+			s := strconv.FormatInt(n, 10)
+			return anyValue{IntValue: &s}
+		}
+		f, _ := v.Float64()
+		return anyValue{DoubleValue: &f}
+	case []interface{}:
+		values := make([]anyValue, len(v))
+		for i, item := range v {
+			values[i] = toAnyValue(item)
+		}
+		return anyValue{ArrayValue: &arrayValue{Values: values}}
+	case map[string]interface{}:
+		kvs := make([]keyValue, 0, len(v))
+		for k, item := range v {
+			kvs = append(kvs, keyValue{Key: k, Value: toAnyValue(item)})
+		}
+		return anyValue{KvlistValue: &kvlistValue{Values: kvs}}
+	case nil:
+		return anyValue{}
+	default:
+		return anyValue{StringValue: strPtr(fmt.Sprint(v))}
+	}
+}
+
+func stringAttribute(key, value string) keyValue {
+	return keyValue{Key: key, Value: anyValue{StringValue: strPtr(value)}}
+}
+
+func strPtr(s string) *string {
+	return &s
+}
+
+// The types below follow the JSON encoding of the OTLP ExportLogsServiceRequest.
+
+type exportRequest struct {
+	ResourceLogs []resourceLogs `json:"resourceLogs"`
+}
+
+type resourceLogs struct {
+	Resource  resource    `json:"resource"`
+	ScopeLogs []scopeLogs `json:"scopeLogs"`
+}
+
+type resource struct {
+	Attributes []keyValue `json:"attributes"`
+}
+
+type scopeLogs struct {
+	Scope      scope       `json:"scope"`
+	LogRecords []logRecord `json:"logRecords"`
+}
+
+type scope struct {
+	Name string `json:"name"`
+}
+
+type logRecord struct {
+	TimeUnixNano         string     `json:"timeUnixNano"`
+	ObservedTimeUnixNano string     `json:"observedTimeUnixNano"`
+	SeverityNumber       int        `json:"severityNumber,omitempty"`
+	SeverityText         string     `json:"severityText,omitempty"`
+	Body                 *anyValue  `json:"body,omitempty"`
+	Attributes           []keyValue `json:"attributes,omitempty"`
+}
+
+type keyValue struct {
+	Key   string   `json:"key"`
+	Value anyValue `json:"value"`
+}
+
+type anyValue struct {
+	StringValue *string      `json:"stringValue,omitempty"`
+	BoolValue   *bool        `json:"boolValue,omitempty"`
+	IntValue    *string      `json:"intValue,omitempty"`
+	DoubleValue *float64     `json:"doubleValue,omitempty"`
+	ArrayValue  *arrayValue  `json:"arrayValue,omitempty"`
+	KvlistValue *kvlistValue `json:"kvlistValue,omitempty"`
+}
+
+type arrayValue struct {
+	Values []anyValue `json:"values"`
+}
+
+type kvlistValue struct {
+	Values []keyValue `json:"values"`
+}
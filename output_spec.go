@@ -0,0 +1,85 @@
+package zerolog_wrapper
+
+import (
+	"errors"
+	"io"
+
+	"github.com/rs/zerolog"
+)
+
+// OutputSpec describes a single destination of WithOutputs.
+type OutputSpec struct {
+	Writer io.Writer
+	// MinLevel is the lowest level written to Writer, all levels when empty.
+	MinLevel LogLevel
+	// Format is the format events are written in, JSONFormat when empty.
+	Format Format
+}
+
+// WithOutputs writes every event to each of specs whose MinLevel it reaches,
+// formatted as the spec says, instead of the default destination.
+//
+// eg: console output of everything to stdout and ECS formatted info events to a file
+//
+//	log.InitLog(log.DebugLevel, "prod", log.WithOutputs([]log.OutputSpec{
+//		{Writer: os.Stdout, MinLevel: log.DebugLevel, Format: log.ConsoleFormat},
+//		{Writer: file, MinLevel: log.InfoLevel, Format: log.ECSFormat},
+//	}))
+//
+// The level of the logger still applies first, so it must be as low as the
+// lowest MinLevel. WithOutput, WithLineEnding and WithGzip only apply to the
+// default destination and SetFormat has no effect on these outputs.
+func WithOutputs(specs []OutputSpec) Option {
+	return func(o *options) {
+		o.outputSpecs = specs
+	}
+}
+
+// specWriter is the writer of a single OutputSpec.
+type specWriter struct {
+	w        *lineWriter
+	minLevel zerolog.Level
+}
+
+// multiOutputWriter writes each event to every output whose minimum level it reaches.
+type multiOutputWriter struct {
+	outs []specWriter
+}
+
+func newMultiOutputWriter(specs []OutputSpec) multiOutputWriter {
+	outs := make([]specWriter, 0, len(specs))
+	for _, spec := range specs {
+		minLevel := zerolog.TraceLevel
+		if spec.MinLevel != "" {
+			minLevel = toZerologLevel(spec.MinLevel)
+		}
+		format := spec.Format
+		if format == "" {
+			format = JSONFormat
+		}
+		outs = append(outs, specWriter{
+			w:        newLineWriter(newFormatWriter(spec.Writer, format)),
+			minLevel: minLevel,
+		})
+	}
+
+	return multiOutputWriter{outs: outs}
+}
+
+func (w multiOutputWriter) Write(p []byte) (int, error) {
+	return w.WriteLevel(zerolog.NoLevel, p)
+}
+
+func (w multiOutputWriter) WriteLevel(level zerolog.Level, p []byte) (int, error) {
+	var errs []error
+	for _, out := range w.outs {
+		if level < out.minLevel {
+			continue
+		}
+		if _, err := out.w.WriteLevel(level, p); err != nil {
+			errs = append(errs, err)
+		}
+	}
+
+	return len(p), errors.Join(errs...)
+}
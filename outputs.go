@@ -0,0 +1,120 @@
+package zerolog_wrapper
+
+import (
+	"bytes"
+	"encoding/json"
+	"io"
+	"sync"
+	"sync/atomic"
+
+	"github.com/rs/zerolog"
+)
+
+// CategoryFieldName is the field RouteCategory routes events by.
+var CategoryFieldName = "category"
+
+// namedOutput is a destination registered through RegisterOutput.
+type namedOutput struct {
+	w        *lineWriter
+	minLevel zerolog.Level
+}
+
+var (
+	outputsMu      sync.RWMutex
+	outputs        = map[string]namedOutput{}
+	categoryRoutes = map[string]string{}
+	// routing is set once a category is routed, so events skip the lookup until then
+	routing atomic.Bool
+)
+
+// RegisterOutput registers w as the output named name, receiving the JSON
+// events of level minLevel or higher routed to it by RouteCategory. Registering
+// a name again replaces its output.
+//
+// eg: audit and access logs to their own files, everything else to the default output
+//
+//	log.RegisterOutput("audit", auditFile, log.InfoLevel)
+//	log.RegisterOutput("access", accessFile, log.InfoLevel)
+//	log.RouteCategory("audit", "audit")
+//	log.RouteCategory("http", "access")
+//
+//	log.Info().Str("category", "audit").Msg("user deleted")
+func RegisterOutput(name string, w io.Writer, minLevel LogLevel) {
+	outputsMu.Lock()
+	defer outputsMu.Unlock()
+
+	outputs[name] = namedOutput{w: newLineWriter(w), minLevel: toZerologLevel(minLevel)}
+}
+
+// RouteCategory sends the events whose category field is category to the
+// output registered as output instead of the default output. Events without
+// a category, or routed to an output which isn't registered, go to the default output.
+func RouteCategory(category, output string) {
+	outputsMu.Lock()
+	defer outputsMu.Unlock()
+
+	categoryRoutes[category] = output
+	routing.Store(true)
+}
+
+// routeFor returns the output the events of category are routed to.
+func routeFor(category string) (namedOutput, bool) {
+	outputsMu.RLock()
+	defer outputsMu.RUnlock()
+
+	name, ok := categoryRoutes[category]
+	if !ok {
+		return namedOutput{}, false
+	}
+	out, ok := outputs[name]
+
+	return out, ok
+}
+
+// categoryRouter writes events to the output their category is routed to, or to out.
+type categoryRouter struct {
+	out zerolog.LevelWriter
+}
+
+func (w categoryRouter) Write(p []byte) (int, error) {
+	return w.WriteLevel(zerolog.NoLevel, p)
+}
+
+func (w categoryRouter) WriteLevel(level zerolog.Level, p []byte) (int, error) {
+	if !routing.Load() || !bytes.Contains(p, []byte(`"`+CategoryFieldName+`":`)) {
+		return w.out.WriteLevel(level, p)
+	}
+
+	category := eventCategory(p)
+	if category == "" {
+		return w.out.WriteLevel(level, p)
+	}
+	out, ok := routeFor(category)
+	if !ok {
+		return w.out.WriteLevel(level, p)
+	}
+
+	if level < out.minLevel {
+		return len(p), nil
+	}
+
+	return out.w.WriteLevel(level, p)
+}
+
+// eventCategory returns the category field of the serialized event p.
+func eventCategory(p []byte) string {
+	fields, err := decodeFields(p)
+	if err != nil {
+		return ""
+	}
+
+	for _, field := range fields {
+		if field.key == CategoryFieldName {
+			var category string
+			_ = json.Unmarshal(field.value, &category)
+			return category
+		}
+	}
+
+	return ""
+}
@@ -0,0 +1,45 @@
+package zerolog_wrapper
+
+import (
+	"time"
+
+	"github.com/rs/zerolog"
+)
+
+// WithPreExit registers fn to run when a fatal or panic event is logged,
+// before the event is written and before the process exits or panics. It
+// receives the level and message of the event, eg: to write a pprof profile
+// to disk for postmortem analysis.
+//
+// fn is given at most timeout to finish so it can't hang shutdown, after
+// which the event carries on regardless. The timeout must be positive.
+func WithPreExit(fn func(level zerolog.Level, msg string), timeout time.Duration) Option {
+	return func(o *options) {
+		o.hooks = append(o.hooks, preExitHook{fn: fn, timeout: timeout})
+	}
+}
+
+type preExitHook struct {
+	fn      func(level zerolog.Level, msg string)
+	timeout time.Duration
+}
+
+func (h preExitHook) Run(e *zerolog.Event, level zerolog.Level, msg string) {
+	if level != zerolog.FatalLevel && level != zerolog.PanicLevel {
+		return
+	}
+
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		h.fn(level, msg)
+	}()
+
+	timer := time.NewTimer(h.timeout)
+	defer timer.Stop()
+
+	select {
+	case <-done:
+	case <-timer.C:
+	}
+}
@@ -0,0 +1,50 @@
+package zerolog_wrapper
+
+import (
+	"io"
+	"testing"
+	"time"
+
+	"github.com/rs/zerolog"
+)
+
+func TestWithPreExitInvalid(t *testing.T) {
+	fn := func(level zerolog.Level, msg string) {}
+
+	tests := []struct {
+		name string
+		opt  Option
+	}{
+		{"zero timeout", WithPreExit(fn, 0)},
+		{"negative timeout", WithPreExit(fn, -time.Second)},
+		{"nil function", WithPreExit(nil, time.Second)},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := New(InfoLevel, Prod, WithOutput(io.Discard), tt.opt); err == nil {
+				t.Error("New accepted the pre-exit hook")
+			}
+		})
+	}
+}
+
+func TestWithPreExit(t *testing.T) {
+	var got []string
+	l, err := New(InfoLevel, Prod, DisableHostIP(), WithOutput(io.Discard), WithPreExit(func(level zerolog.Level, msg string) {
+		got = append(got, level.String()+" "+msg)
+	}, time.Second))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	l.Error().Msg("not fatal")
+	func() {
+		defer func() { _ = recover() }()
+		l.Panic().Msg("boom")
+	}()
+
+	if len(got) != 1 || got[0] != "panic boom" {
+		t.Errorf("pre-exit ran for %v, want only the panic event", got)
+	}
+}
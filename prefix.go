@@ -0,0 +1,53 @@
+package zerolog_wrapper
+
+import (
+	"sync"
+	"sync/atomic"
+
+	"github.com/rs/zerolog"
+)
+
+// PrefixFieldName is the field SetPrefix adds to the events of the global logger.
+var PrefixFieldName = "service"
+
+var (
+	// prefixMu serializes SetPrefix and its restore functions
+	prefixMu sync.Mutex
+	prefix   atomic.Value
+)
+
+// SetPrefix adds prefix as the service field, named by PrefixFieldName, to
+// every event of the global logger, eg: to tell apart the logical services of
+// a monorepo binary without setting up an Instance for each. An empty prefix
+// removes the field. Unlike UpdateContext, a later call replaces the prefix
+// instead of adding a second field.
+//
+// The returned function restores the previous prefix, to scope it:
+//
+//	restore := log.SetPrefix("billing")
+//	defer restore()
+func SetPrefix(p string) (restore func()) {
+	prefixMu.Lock()
+	defer prefixMu.Unlock()
+
+	previous, _ := prefix.Load().(string)
+	prefix.Store(p)
+
+	var once sync.Once
+	return func() {
+		once.Do(func() {
+			prefixMu.Lock()
+			defer prefixMu.Unlock()
+			prefix.Store(previous)
+		})
+	}
+}
+
+// prefixHook adds the prefix set by SetPrefix.
+type prefixHook struct{}
+
+func (prefixHook) Run(e *zerolog.Event, level zerolog.Level, msg string) {
+	if p, _ := prefix.Load().(string); p != "" {
+		e.Str(PrefixFieldName, p)
+	}
+}
@@ -0,0 +1,159 @@
+// Package protolog logs protobuf messages as structured JSON objects instead
+// of their unreadable default formatting.
+//
+// It is kept apart from zerolog_wrapper so the main package doesn't depend on protobuf.
+//
+//	log.Info().Func(protolog.Proto("request", req, "password")).Msg("rpc received")
+//	// Output: {"level":"info","request":{"user":"jane","password":"[REDACTED]"},"message":"rpc received"}
+package protolog
+
+import (
+	"encoding/json"
+
+	"github.com/rs/zerolog"
+	"google.golang.org/protobuf/encoding/protojson"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/reflect/protoreflect"
+	"google.golang.org/protobuf/reflect/protoregistry"
+	"google.golang.org/protobuf/types/descriptorpb"
+)
+
+// redactedValue replaces the value of redacted fields, like it does in zerolog_wrapper.
+const redactedValue = "[REDACTED]"
+
+// Proto adds msg under key as a nested object marshaled with protojson.
+// Fields marked with the debug_redact field option, as well as fields whose
+// name is in redact, are logged as "[REDACTED]" at any depth: in nested
+// messages, lists and map values as well as in messages packed in an Any
+// whose type is registered. Names match either the proto name or the JSON
+// name of a field.
+//
+// Use it with the Func method of an event. Messages which fail to marshal are
+// logged with the error under key instead.
+func Proto(key string, msg proto.Message, redact ...string) func(e *zerolog.Event) {
+	return func(e *zerolog.Event) {
+		if msg == nil {
+			e.Interface(key, nil)
+			return
+		}
+
+		b, err := protojson.Marshal(msg)
+		if err != nil {
+			e.Str(key, "marshal error: "+err.Error())
+			return
+		}
+
+		var obj map[string]interface{}
+		if err := json.Unmarshal(b, &obj); err != nil {
+			e.RawJSON(key, b)
+			return
+		}
+
+		names := make(map[string]bool, len(redact))
+		for _, name := range redact {
+			names[name] = true
+		}
+		redactFields(msg.ProtoReflect(), obj, names)
+
+		b, err = json.Marshal(obj)
+		if err != nil {
+			e.Str(key, "marshal error: "+err.Error())
+			return
+		}
+		e.RawJSON(key, b)
+	}
+}
+
+// anyFullName is the name of google.protobuf.Any, which protojson writes as
+// the fields of the packed message next to "@type".
+const anyFullName protoreflect.FullName = "google.protobuf.Any"
+
+// redactFields replaces the redacted fields of m in its JSON object obj,
+// descending into nested messages.
+func redactFields(m protoreflect.Message, obj map[string]interface{}, names map[string]bool) {
+	if m.Descriptor().FullName() == anyFullName {
+		packed, ok := unpackAny(m)
+		if !ok {
+			return
+		}
+		m = packed
+	}
+
+	fields := m.Descriptor().Fields()
+	for i := 0; i < fields.Len(); i++ {
+		fd := fields.Get(i)
+		name := fd.JSONName()
+		value, ok := obj[name]
+		if !ok {
+			continue
+		}
+
+		if isRedacted(fd, names) {
+			obj[name] = redactedValue
+			continue
+		}
+
+		if fd.IsMap() {
+			entries, _ := value.(map[string]interface{})
+			if fd.MapValue().Message() == nil || entries == nil {
+				continue
+			}
+			// protojson writes the map keys in their string form
+			m.Get(fd).Map().Range(func(k protoreflect.MapKey, v protoreflect.Value) bool {
+				if nested, ok := entries[k.String()].(map[string]interface{}); ok {
+					redactFields(v.Message(), nested, names)
+				}
+				return true
+			})
+			continue
+		}
+
+		if fd.Message() == nil {
+			continue
+		}
+
+		if fd.IsList() {
+			list := m.Get(fd).List()
+			items, _ := value.([]interface{})
+			for j := 0; j < list.Len() && j < len(items); j++ {
+				if nested, ok := items[j].(map[string]interface{}); ok {
+					redactFields(list.Get(j).Message(), nested, names)
+				}
+			}
+			continue
+		}
+
+		// well known types such as Timestamp marshal to plain JSON values
+		if nested, ok := value.(map[string]interface{}); ok {
+			redactFields(m.Get(fd).Message(), nested, names)
+		}
+	}
+}
+
+// unpackAny returns the message packed in the Any m, which like protojson
+// it looks up in the global registry.
+func unpackAny(m protoreflect.Message) (protoreflect.Message, bool) {
+	fields := m.Descriptor().Fields()
+	typeURL := m.Get(fields.ByName("type_url")).String()
+	mt, err := protoregistry.GlobalTypes.FindMessageByURL(typeURL)
+	if err != nil {
+		return nil, false
+	}
+
+	packed := mt.New()
+	if err := proto.Unmarshal(m.Get(fields.ByName("value")).Bytes(), packed.Interface()); err != nil {
+		return nil, false
+	}
+
+	return packed, true
+}
+
+func isRedacted(fd protoreflect.FieldDescriptor, names map[string]bool) bool {
+	if names[string(fd.Name())] || names[fd.JSONName()] {
+		return true
+	}
+
+	opts, ok := fd.Options().(*descriptorpb.FieldOptions)
+
+	return ok && opts.GetDebugRedact()
+}
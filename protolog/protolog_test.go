@@ -0,0 +1,135 @@
+package protolog
+
+import (
+	"bytes"
+	"encoding/json"
+	"testing"
+
+	"github.com/rs/zerolog"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/reflect/protodesc"
+	"google.golang.org/protobuf/reflect/protoreflect"
+	"google.golang.org/protobuf/reflect/protoregistry"
+	"google.golang.org/protobuf/types/descriptorpb"
+	"google.golang.org/protobuf/types/dynamicpb"
+	"google.golang.org/protobuf/types/known/anypb"
+)
+
+// testTypes builds the messages
+//
+//	message Credentials { string user = 1; string password = 2; }
+//	message Request {
+//		Credentials credentials = 1;
+//		repeated Credentials history = 2;
+//		map<string, Credentials> by_name = 3;
+//		google.protobuf.Any extra = 4;
+//	}
+//
+// registering Credentials so it can be packed in an Any.
+func testTypes(t *testing.T) (credentials, request protoreflect.MessageType) {
+	t.Helper()
+
+	optional := descriptorpb.FieldDescriptorProto_LABEL_OPTIONAL.Enum()
+	repeated := descriptorpb.FieldDescriptorProto_LABEL_REPEATED.Enum()
+	str := descriptorpb.FieldDescriptorProto_TYPE_STRING.Enum()
+	msg := descriptorpb.FieldDescriptorProto_TYPE_MESSAGE.Enum()
+
+	fdp := &descriptorpb.FileDescriptorProto{
+		Name:       proto.String("protolog_test.proto"),
+		Package:    proto.String("protolog.test"),
+		Syntax:     proto.String("proto3"),
+		Dependency: []string{"google/protobuf/any.proto"},
+		MessageType: []*descriptorpb.DescriptorProto{
+			{
+				Name: proto.String("Credentials"),
+				Field: []*descriptorpb.FieldDescriptorProto{
+					{Name: proto.String("user"), Number: proto.Int32(1), Label: optional, Type: str, JsonName: proto.String("user")},
+					{Name: proto.String("password"), Number: proto.Int32(2), Label: optional, Type: str, JsonName: proto.String("password")},
+				},
+			},
+			{
+				Name: proto.String("Request"),
+				Field: []*descriptorpb.FieldDescriptorProto{
+					{Name: proto.String("credentials"), Number: proto.Int32(1), Label: optional, Type: msg, TypeName: proto.String(".protolog.test.Credentials"), JsonName: proto.String("credentials")},
+					{Name: proto.String("history"), Number: proto.Int32(2), Label: repeated, Type: msg, TypeName: proto.String(".protolog.test.Credentials"), JsonName: proto.String("history")},
+					{Name: proto.String("by_name"), Number: proto.Int32(3), Label: repeated, Type: msg, TypeName: proto.String(".protolog.test.Request.ByNameEntry"), JsonName: proto.String("byName")},
+					{Name: proto.String("extra"), Number: proto.Int32(4), Label: optional, Type: msg, TypeName: proto.String(".google.protobuf.Any"), JsonName: proto.String("extra")},
+				},
+				NestedType: []*descriptorpb.DescriptorProto{{
+					Name:    proto.String("ByNameEntry"),
+					Options: &descriptorpb.MessageOptions{MapEntry: proto.Bool(true)},
+					Field: []*descriptorpb.FieldDescriptorProto{
+						{Name: proto.String("key"), Number: proto.Int32(1), Label: optional, Type: str, JsonName: proto.String("key")},
+						{Name: proto.String("value"), Number: proto.Int32(2), Label: optional, Type: msg, TypeName: proto.String(".protolog.test.Credentials"), JsonName: proto.String("value")},
+					},
+				}},
+			},
+		},
+	}
+
+	fd, err := protodesc.NewFile(fdp, protoregistry.GlobalFiles)
+	if err != nil {
+		t.Fatal(err)
+	}
+	credentials = dynamicpb.NewMessageType(fd.Messages().ByName("Credentials"))
+	request = dynamicpb.NewMessageType(fd.Messages().ByName("Request"))
+
+	if _, err := protoregistry.GlobalTypes.FindMessageByName(credentials.Descriptor().FullName()); err != nil {
+		if err := protoregistry.GlobalTypes.RegisterMessage(credentials); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	return credentials, request
+}
+
+func TestProtoRedactsAtAnyDepth(t *testing.T) {
+	credentialsType, requestType := testTypes(t)
+
+	newCredentials := func(user string) protoreflect.Message {
+		c := credentialsType.New()
+		fields := c.Descriptor().Fields()
+		c.Set(fields.ByName("user"), protoreflect.ValueOfString(user))
+		c.Set(fields.ByName("password"), protoreflect.ValueOfString("secret"))
+		return c
+	}
+
+	req := requestType.New()
+	fields := req.Descriptor().Fields()
+	req.Set(fields.ByName("credentials"), protoreflect.ValueOfMessage(newCredentials("direct")))
+	req.Mutable(fields.ByName("history")).List().Append(protoreflect.ValueOfMessage(newCredentials("listed")))
+	req.Mutable(fields.ByName("by_name")).Map().Set(protoreflect.ValueOfString("jane").MapKey(), protoreflect.ValueOfMessage(newCredentials("mapped")))
+	extra, err := anypb.New(newCredentials("packed").Interface())
+	if err != nil {
+		t.Fatal(err)
+	}
+	req.Set(fields.ByName("extra"), protoreflect.ValueOfMessage(extra.ProtoReflect()))
+
+	var buf bytes.Buffer
+	l := zerolog.New(&buf)
+	l.Info().Func(Proto("request", req.Interface(), "password")).Msg("")
+
+	var event struct {
+		Request struct {
+			Credentials map[string]string            `json:"credentials"`
+			History     []map[string]string          `json:"history"`
+			ByName      map[string]map[string]string `json:"byName"`
+			Extra       map[string]string            `json:"extra"`
+		} `json:"request"`
+	}
+	if err := json.Unmarshal(buf.Bytes(), &event); err != nil {
+		t.Fatalf("%v: %s", err, buf.Bytes())
+	}
+
+	r := event.Request
+	for name, c := range map[string]map[string]string{
+		"direct": r.Credentials,
+		"listed": r.History[0],
+		"mapped": r.ByName["jane"],
+		"packed": r.Extra,
+	} {
+		if c["user"] != name || c["password"] != redactedValue {
+			t.Errorf("%s credentials = %v, want the password redacted", name, c)
+		}
+	}
+}
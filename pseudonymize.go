@@ -0,0 +1,79 @@
+package zerolog_wrapper
+
+import (
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"sync"
+)
+
+var (
+	processSaltOnce sync.Once
+	processSalt     []byte
+)
+
+// WithPseudonymize replaces the values of the named top level fields, eg:
+// user_id, with a salted hash before the event is written. Equal values
+// hash equally, so events of the same user can still be correlated, while
+// the user can't be identified from the logs without the salt.
+// Unlike redaction, which masks the value, this keeps it correlatable.
+//
+// A nil salt uses a random salt generated once per process, so hashes can
+// only be correlated within the lifetime of the process. Pass a fixed salt
+// to correlate across restarts and instances. null values are kept.
+//
+// eg:
+//
+//	log.InitLog(log.InfoLevel, "prod", log.WithPseudonymize(nil, "user_id", "email"))
+//	log.Info().Str("user_id", "42").Msg("login")
+//	// Output: {"level":"info","user_id":"3c1f6ad0e6a84c7e9b1d7f6b3a11e5c2","message":"login"}
+func WithPseudonymize(salt []byte, keys ...string) Option {
+	if salt == nil {
+		salt = perProcessSalt()
+	}
+
+	return func(o *options) {
+		o.transforms = append(o.transforms, pseudonymize(salt, keys))
+	}
+}
+
+// perProcessSalt returns the random salt of the process.
+func perProcessSalt() []byte {
+	processSaltOnce.Do(func() {
+		processSalt = make([]byte, 32)
+		// crypto/rand doesn't fail on supported platforms
+		_, _ = rand.Read(processSalt)
+	})
+
+	return processSalt
+}
+
+func pseudonymize(salt []byte, keys []string) fieldTransform {
+	names := make(map[string]bool, len(keys))
+	for _, key := range keys {
+		names[key] = true
+	}
+
+	return func(fields []jsonField) []jsonField {
+		for i, field := range fields {
+			if !names[field.key] || string(field.value) == "null" {
+				continue
+			}
+
+			// hash strings by their text, so escaping doesn't change the hash
+			value := []byte(field.value)
+			var s string
+			if err := json.Unmarshal(field.value, &s); err == nil {
+				value = []byte(s)
+			}
+
+			mac := hmac.New(sha256.New, salt)
+			mac.Write(value)
+			fields[i].value = []byte(`"` + hex.EncodeToString(mac.Sum(nil)[:16]) + `"`)
+		}
+
+		return fields
+	}
+}
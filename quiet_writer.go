@@ -0,0 +1,93 @@
+package zerolog_wrapper
+
+import (
+	"sync"
+
+	"github.com/rs/zerolog"
+)
+
+// WithQuietUntilError keeps events below error level in a ring buffer holding
+// the last size events instead of writing them out. When an error, fatal or
+// panic event is logged the buffered events are written first, oldest first,
+// followed by the triggering event.
+//
+// This gives CLI tools clean output on success and full detail on failure.
+func WithQuietUntilError(size int) Option {
+	return func(o *options) {
+		o.quietBufferSize = size
+	}
+}
+
+// quietWriter buffers low level events until an error level event shows up.
+type quietWriter struct {
+	mu   sync.Mutex
+	out  zerolog.LevelWriter
+	buf  []quietEntry
+	next int
+	full bool
+}
+
+// quietEntry is a buffered event together with its level.
+type quietEntry struct {
+	level zerolog.Level
+	p     []byte
+}
+
+func newQuietWriter(out zerolog.LevelWriter, size int) *quietWriter {
+	return &quietWriter{
+		out: out,
+		buf: make([]quietEntry, size),
+	}
+}
+
+func (w *quietWriter) Write(p []byte) (int, error) {
+	return w.WriteLevel(zerolog.NoLevel, p)
+}
+
+func (w *quietWriter) WriteLevel(level zerolog.Level, p []byte) (int, error) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	if level < zerolog.ErrorLevel || level > zerolog.PanicLevel {
+		// zerolog reuses the event buffer once the write returns
+		w.buf[w.next] = quietEntry{level: level, p: append([]byte(nil), p...)}
+		w.next = (w.next + 1) % len(w.buf)
+		if w.next == 0 {
+			w.full = true
+		}
+
+		return len(p), nil
+	}
+
+	if err := w.flush(); err != nil {
+		return 0, err
+	}
+
+	return w.out.WriteLevel(level, p)
+}
+
+// flush writes out the buffered events in the order they were logged, each
+// with its own level, so level aware writers after it such as WithOutputs see them as logged.
+func (w *quietWriter) flush() error {
+	start := 0
+	if w.full {
+		start = w.next
+	}
+	for i := 0; i < len(w.buf); i++ {
+		entry := w.buf[(start+i)%len(w.buf)]
+		if entry.p == nil {
+			continue
+		}
+		if _, err := w.out.WriteLevel(entry.level, entry.p); err != nil {
+			return err
+		}
+	}
+
+	for i := range w.buf {
+		w.buf[i] = quietEntry{}
+	}
+	w.next = 0
+	w.full = false
+
+	return nil
+}
@@ -0,0 +1,38 @@
+package zerolog_wrapper
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+)
+
+func TestQuietWriterReplaysEventsWithLevel(t *testing.T) {
+	var all, warnings bytes.Buffer
+	l, err := New(DebugLevel, Prod, DisableHostIP(), WithQuietUntilError(3), WithOutputs([]OutputSpec{
+		{Writer: &all, MinLevel: DebugLevel},
+		{Writer: &warnings, MinLevel: WarnLevel},
+	}))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	l.Debug().Msg("dropped from the ring buffer")
+	l.Debug().Msg("debug")
+	l.Info().Msg("info")
+	l.Warn().Msg("warn")
+	if all.Len() != 0 {
+		t.Fatalf("events written before an error:\n%s", all.String())
+	}
+
+	l.Error().Msg("error")
+
+	if got := strings.Count(all.String(), "\n"); got != 4 {
+		t.Errorf("got %d events, want 4:\n%s", got, all.String())
+	}
+	if strings.Contains(all.String(), "dropped") {
+		t.Errorf("oldest event wasn't dropped from the ring buffer:\n%s", all.String())
+	}
+	if got := strings.Count(warnings.String(), "\n"); got != 2 || strings.Contains(warnings.String(), `"level":"info"`) {
+		t.Errorf("want only the warn and error events at the warn output:\n%s", warnings.String())
+	}
+}
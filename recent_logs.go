@@ -0,0 +1,108 @@
+package zerolog_wrapper
+
+import (
+	"bytes"
+	"net/http"
+	"sync"
+
+	"github.com/rs/zerolog"
+)
+
+// WithRecentLogs keeps the last size events in memory so DebugLogsHandler can
+// serve them. The events are kept as they are written out, after options such
+// as WithAllowedFields and WithPseudonymize were applied.
+func WithRecentLogs(size int) Option {
+	return func(o *options) {
+		o.recentLogsSize = size
+	}
+}
+
+// recentLogsBuffer is a thread-safe ring buffer of serialized events.
+type recentLogsBuffer struct {
+	mu      sync.Mutex
+	entries [][]byte
+	next    int
+	full    bool
+}
+
+// reset sizes the buffer to hold size events, dropping anything kept so far.
+func (b *recentLogsBuffer) reset(size int) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	b.entries = make([][]byte, size)
+	b.next = 0
+	b.full = false
+}
+
+func (b *recentLogsBuffer) add(p []byte) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	if len(b.entries) == 0 {
+		return
+	}
+	b.entries[b.next] = bytes.TrimSuffix(append([]byte(nil), p...), []byte("\n"))
+	b.next = (b.next + 1) % len(b.entries)
+	if b.next == 0 {
+		b.full = true
+	}
+}
+
+// snapshot returns the kept events, oldest first.
+func (b *recentLogsBuffer) snapshot() [][]byte {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	if !b.full {
+		return append([][]byte(nil), b.entries[:b.next]...)
+	}
+
+	return append(append([][]byte(nil), b.entries[b.next:]...), b.entries[:b.next]...)
+}
+
+// recentLogsWriter keeps a copy of each event in recentLogs before passing it on.
+type recentLogsWriter struct {
+	out        zerolog.LevelWriter
+	recentLogs *recentLogsBuffer
+}
+
+func (w recentLogsWriter) Write(p []byte) (int, error) {
+	return w.WriteLevel(zerolog.NoLevel, p)
+}
+
+func (w recentLogsWriter) WriteLevel(level zerolog.Level, p []byte) (int, error) {
+	w.recentLogs.add(p)
+
+	return w.out.WriteLevel(level, p)
+}
+
+// DebugLogsHandler returns an http.Handler serving the events kept by
+// WithRecentLogs as a JSON array, oldest first. Mount it on an internal
+// admin endpoint only, the events may contain sensitive data.
+//
+// eg:
+//
+//	http.Handle("/debug/logs", log.DebugLogsHandler())
+func DebugLogsHandler() http.Handler {
+	return std.DebugLogsHandler()
+}
+
+// DebugLogsHandler returns an http.Handler serving the events kept by the instance, see DebugLogsHandler.
+func (i *Instance) DebugLogsHandler() http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+
+		var buf bytes.Buffer
+		buf.WriteByte('[')
+		for n, entry := range i.recentLogs.snapshot() {
+			if n > 0 {
+				buf.WriteByte(',')
+			}
+			buf.Write(entry)
+		}
+		buf.WriteString("]\n")
+
+		_, _ = w.Write(buf.Bytes())
+	})
+}
@@ -0,0 +1,77 @@
+package zerolog_wrapper
+
+import (
+	"encoding/json"
+	"io"
+	"net/http/httptest"
+	"testing"
+)
+
+// debugLogs returns the events served by the DebugLogsHandler of l.
+func debugLogs(t *testing.T, l *Instance) []map[string]interface{} {
+	t.Helper()
+
+	rec := httptest.NewRecorder()
+	l.DebugLogsHandler().ServeHTTP(rec, httptest.NewRequest("GET", "/debug/logs", nil))
+
+	var events []map[string]interface{}
+	if err := json.Unmarshal(rec.Body.Bytes(), &events); err != nil {
+		t.Fatalf("%v: %s", err, rec.Body.String())
+	}
+	return events
+}
+
+func TestDebugLogsHandler(t *testing.T) {
+	l, err := New(InfoLevel, Prod, DisableHostIP(), WithOutput(io.Discard), WithRecentLogs(2))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	l.Info().Msg("one")
+	l.Info().Msg("two")
+	l.Info().Msg("three")
+
+	events := debugLogs(t, l)
+	if len(events) != 2 || events[0]["message"] != "two" || events[1]["message"] != "three" {
+		t.Errorf("served %v, want the last two events oldest first", events)
+	}
+}
+
+func TestDebugLogsHandlerServesAllowedFieldsOnly(t *testing.T) {
+	l, err := New(InfoLevel, Prod, WithOutput(io.Discard), WithRecentLogs(10), WithAllowedFields(false, "request_id"))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	l.Info().Str("request_id", "r").Str("email", "a@b.c").Str("user_id", "42").Msg("signup")
+
+	events := debugLogs(t, l)
+	if len(events) != 1 {
+		t.Fatalf("served %d events, want 1", len(events))
+	}
+	for _, key := range []string{"email", "user_id", "host_ip"} {
+		if _, ok := events[0][key]; ok {
+			t.Errorf("dropped field %s served by the debug endpoint: %v", key, events[0])
+		}
+	}
+	if events[0]["request_id"] != "r" {
+		t.Errorf("allowed field missing: %v", events[0])
+	}
+}
+
+func TestDebugLogsHandlerServesPseudonymizedFields(t *testing.T) {
+	l, err := New(InfoLevel, Prod, DisableHostIP(), WithOutput(io.Discard), WithRecentLogs(10), WithPseudonymize([]byte("salt"), "user_id"))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	l.Info().Str("user_id", "42").Msg("login")
+
+	events := debugLogs(t, l)
+	if len(events) != 1 {
+		t.Fatalf("served %d events, want 1", len(events))
+	}
+	if userID, _ := events[0]["user_id"].(string); userID == "" || userID == "42" {
+		t.Errorf("user_id served in cleartext by the debug endpoint: %v", events[0])
+	}
+}
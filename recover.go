@@ -0,0 +1,38 @@
+package zerolog_wrapper
+
+import (
+	"fmt"
+	"runtime/debug"
+
+	"github.com/rs/zerolog"
+)
+
+// Recover recovers from a panic and logs it at error level together with the
+// type of the panic value and the stack of the panicking goroutine.
+//
+// It must be deferred directly for recover to take effect:
+//
+//	defer log.Recover()
+func Recover() {
+	if r := recover(); r != nil {
+		panicFields(Error(), r, debug.Stack()).Msg("recovered from panic")
+	}
+}
+
+// panicFields adds the panic value r, keeping errors, strings and structured
+// values apart, plus the recovered marker and stack to the event.
+func panicFields(e *zerolog.Event, r interface{}, stack []byte) *zerolog.Event {
+	switch v := r.(type) {
+	case error:
+		e = e.AnErr("panic", v)
+	case string:
+		e = e.Str("panic", v)
+	default:
+		e = e.Interface("panic", v)
+	}
+
+	return e.
+		Str("panic_type", fmt.Sprintf("%T", r)).
+		Bool("recovered", true).
+		Str("stack", string(stack))
+}
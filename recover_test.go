@@ -0,0 +1,114 @@
+package zerolog_wrapper
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"io"
+	"strings"
+	"sync"
+	"testing"
+)
+
+type panicValue struct {
+	Code   int    `json:"code"`
+	Reason string `json:"reason"`
+}
+
+// useStd swaps the global logger for one writing to the returned buffer.
+func useStd(t *testing.T, opts ...Option) *bytes.Buffer {
+	t.Helper()
+
+	var buf bytes.Buffer
+	useStdOutput(t, &buf, opts...)
+
+	return &buf
+}
+
+// useStdOutput swaps the global logger for one writing to w.
+func useStdOutput(t *testing.T, w io.Writer, opts ...Option) {
+	t.Helper()
+
+	l, err := New(InfoLevel, Prod, append([]Option{DisableHostIP(), WithOutput(w)}, opts...)...)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	previous := std
+	std = l
+	t.Cleanup(func() { std = previous })
+}
+
+// lockedBuffer is a bytes.Buffer safe for concurrent use.
+type lockedBuffer struct {
+	mu  sync.Mutex
+	buf bytes.Buffer
+}
+
+func (b *lockedBuffer) Write(p []byte) (int, error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	return b.buf.Write(p)
+}
+
+func (b *lockedBuffer) Bytes() []byte {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	return append([]byte(nil), b.buf.Bytes()...)
+}
+
+func TestRecover(t *testing.T) {
+	tests := []struct {
+		name      string
+		value     interface{}
+		wantPanic interface{}
+		wantType  string
+	}{
+		{"error", errors.New("boom"), "boom", "*errors.errorString"},
+		{"string", "boom", "boom", "string"},
+		{"struct", panicValue{Code: 7, Reason: "boom"}, map[string]interface{}{"code": float64(7), "reason": "boom"}, "zerolog_wrapper.panicValue"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			buf := useStd(t)
+
+			func() {
+				defer Recover()
+				panic(tt.value)
+			}()
+
+			var event map[string]interface{}
+			if err := json.Unmarshal(buf.Bytes(), &event); err != nil {
+				t.Fatalf("%v: %s", err, buf.String())
+			}
+			if event["level"] != "error" || event["message"] != "recovered from panic" {
+				t.Errorf("unexpected event: %s", buf.String())
+			}
+			if got, _ := json.Marshal(event["panic"]); !bytes.Equal(got, mustMarshal(t, tt.wantPanic)) {
+				t.Errorf("panic = %s, want %s", got, mustMarshal(t, tt.wantPanic))
+			}
+			if event["panic_type"] != tt.wantType {
+				t.Errorf("panic_type = %v, want %s", event["panic_type"], tt.wantType)
+			}
+			if event["recovered"] != true {
+				t.Errorf("recovered = %v, want true", event["recovered"])
+			}
+			if stack, _ := event["stack"].(string); !strings.Contains(stack, "TestRecover") {
+				t.Errorf("stack does not contain the panicking goroutine: %q", stack)
+			}
+		})
+	}
+}
+
+func mustMarshal(t *testing.T, v interface{}) []byte {
+	t.Helper()
+
+	b, err := json.Marshal(v)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return b
+}
@@ -0,0 +1,25 @@
+package zerolog_wrapper
+
+// Result logs the outcome of operation. A nil err is logged at info level
+// with success set to true, anything else at error level with success set
+// to false and the error, so success rates can be derived from the logs.
+//
+// eg:
+//
+//	err := sendInvoice()
+//	log.Result("send_invoice", err)
+func Result(operation string, err error) {
+	if err != nil {
+		Error().
+			Str("operation", operation).
+			Bool("success", false).
+			Err(err).
+			Msg(operation + " failed")
+		return
+	}
+
+	Info().
+		Str("operation", operation).
+		Bool("success", true).
+		Msg(operation + " succeeded")
+}
@@ -0,0 +1,36 @@
+package zerolog_wrapper
+
+import "time"
+
+// LogRetry logs a failed attempt of a retried operation with the attempt
+// number, the maximum number of attempts and the backoff before the next one.
+//
+// While attempts are left it logs at warn level, once attempt reaches max the
+// final failure is logged at error level without a backoff.
+//
+// eg:
+//
+//	for attempt := 1; attempt <= max; attempt++ {
+//		if err = call(); err == nil {
+//			break
+//		}
+//		log.LogRetry(attempt, max, err, backoff)
+//		time.Sleep(backoff)
+//	}
+func LogRetry(attempt, max int, err error, nextBackoff time.Duration) {
+	if attempt >= max {
+		Error().
+			Err(err).
+			Int("attempt", attempt).
+			Int("max_attempts", max).
+			Msg("giving up after final attempt")
+		return
+	}
+
+	Warn().
+		Err(err).
+		Int("attempt", attempt).
+		Int("max_attempts", max).
+		Int64("backoff_ms", nextBackoff.Milliseconds()).
+		Msg("attempt failed, retrying")
+}
@@ -0,0 +1,117 @@
+package zerolog_wrapper
+
+import (
+	"time"
+
+	"github.com/rs/zerolog"
+)
+
+// SamplerConfig sets the sampling rate of each level. A rate of N keeps one
+// in every N events of that level, 0 and 1 keep all of them. Error, fatal
+// and panic events are never sampled.
+type SamplerConfig struct {
+	TraceRate uint32
+	DebugRate uint32
+	InfoRate  uint32
+	WarnRate  uint32
+
+	// WarmUp keeps every event for this long after the logger is set up, eg:
+	// to capture startup issues in full, before the rates apply.
+	WarmUp time.Duration
+}
+
+// WithSampling samples trace to warn events according to config, while every
+// error and higher event is kept. Sampled out events are counted in Stats.
+//
+// eg: keep one in ten debug and info events
+//
+//	log.InitLog(log.DebugLevel, "prod", log.WithSampling(log.SamplerConfig{DebugRate: 10, InfoRate: 10}))
+func WithSampling(config SamplerConfig) Option {
+	return func(o *options) {
+		o.sampling = &config
+	}
+}
+
+// sampler returns the zerolog sampler for config, for a logger set up at start.
+func (config SamplerConfig) sampler(start time.Time) zerolog.Sampler {
+	if config.WarmUp > 0 {
+		return warmUpSampler{until: start.Add(config.WarmUp), sampler: config.levelSampler()}
+	}
+
+	return config.levelSampler()
+}
+
+// levelSampler returns the per level zerolog sampler for config.
+func (config SamplerConfig) levelSampler() zerolog.LevelSampler {
+	rate := func(n uint32) zerolog.Sampler {
+		if n <= 1 {
+			return nil
+		}
+		return &zerolog.BasicSampler{N: n}
+	}
+
+	// ErrorSampler is left unset so errors always get through
+	return zerolog.LevelSampler{
+		TraceSampler: rate(config.TraceRate),
+		DebugSampler: rate(config.DebugRate),
+		InfoSampler:  rate(config.InfoRate),
+		WarnSampler:  rate(config.WarnRate),
+	}
+}
+
+// warmUpSampler keeps every event until the warm-up period is over.
+type warmUpSampler struct {
+	until   time.Time
+	sampler zerolog.Sampler
+}
+
+func (s warmUpSampler) Sample(level zerolog.Level) bool {
+	if time.Now().Before(s.until) {
+		return true
+	}
+
+	return s.sampler.Sample(level)
+}
+
+// countingSampler counts the events its sampler drops in Stats.
+type countingSampler struct {
+	sampler  zerolog.Sampler
+	counters *eventCounters
+}
+
+func (s countingSampler) Sample(level zerolog.Level) bool {
+	if s.sampler.Sample(level) {
+		return true
+	}
+	s.counters.countSampledOut(level)
+
+	return false
+}
+
+// WithOnSampledOut calls fn with the level and message of each event dropped
+// by WithSampling, eg: to keep incrementing a metric for events which aren't
+// logged. fn runs synchronously on the logging goroutine and must be cheap.
+//
+// Without this option sampling happens before the event is built, with it
+// each event is built up to its message before the sampling decision.
+func WithOnSampledOut(fn func(level LogLevel, msg string)) Option {
+	return func(o *options) {
+		o.onSampledOut = fn
+	}
+}
+
+// samplingHook samples events once their message is known, reporting the dropped ones to onSampledOut.
+type samplingHook struct {
+	sampler      zerolog.Sampler
+	counters     *eventCounters
+	onSampledOut func(level LogLevel, msg string)
+}
+
+func (h samplingHook) Run(e *zerolog.Event, level zerolog.Level, msg string) {
+	if isContextRender(msg) || h.sampler.Sample(level) {
+		return
+	}
+	h.counters.countSampledOut(level)
+	h.onSampledOut(LogLevel(level.String()), msg)
+	e.Discard()
+}
@@ -0,0 +1,44 @@
+package zerolog_wrapper
+
+import (
+	"bytes"
+	"testing"
+)
+
+func TestSamplingKeepsErrors(t *testing.T) {
+	var buf bytes.Buffer
+	config := SamplerConfig{TraceRate: 1000, DebugRate: 1000, InfoRate: 1000, WarnRate: 1000}
+	l, err := New(TraceLevel, Prod, DisableHostIP(), WithOutput(&buf), WithSampling(config))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	const events = 1000
+	for n := 0; n < events; n++ {
+		l.Info().Int("n", n).Msg("sampled")
+		l.Error().Int("n", n).Msg("kept")
+	}
+
+	if got := bytes.Count(buf.Bytes(), []byte(`"level":"error"`)); got != events {
+		t.Errorf("got %d error events, want all %d", got, events)
+	}
+	if got := bytes.Count(buf.Bytes(), []byte(`"level":"info"`)); got != 1 {
+		t.Errorf("got %d info events, want 1 in %d", got, events)
+	}
+}
+
+func TestSamplingRateOfOneKeepsAll(t *testing.T) {
+	var buf bytes.Buffer
+	l, err := New(InfoLevel, Prod, DisableHostIP(), WithOutput(&buf), WithSampling(SamplerConfig{InfoRate: 1}))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for n := 0; n < 10; n++ {
+		l.Info().Msg("kept")
+	}
+
+	if got := bytes.Count(buf.Bytes(), []byte("\n")); got != 10 {
+		t.Errorf("got %d events, want 10", got)
+	}
+}
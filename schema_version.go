@@ -0,0 +1,10 @@
+package zerolog_wrapper
+
+// WithSchemaVersion adds version as log_schema_version to every event, so
+// downstream parsers know which field layout produced a line. Bump it
+// whenever the layout of the events changes.
+func WithSchemaVersion(version int) Option {
+	return func(o *options) {
+		o.schemaVersion = &version
+	}
+}
@@ -0,0 +1,34 @@
+package zerolog_wrapper
+
+import "github.com/rs/zerolog"
+
+// OTelSeverityNumbers maps levels onto the OpenTelemetry log severity numbers.
+// Panic is placed just above fatal, following zerolog's level order.
+var OTelSeverityNumbers = map[zerolog.Level]int{
+	zerolog.TraceLevel: 1,
+	zerolog.DebugLevel: 5,
+	zerolog.InfoLevel:  9,
+	zerolog.WarnLevel:  13,
+	zerolog.ErrorLevel: 17,
+	zerolog.FatalLevel: 21,
+	zerolog.PanicLevel: 22,
+}
+
+// WithSeverityNumber adds a numeric severity_number field next to the level of
+// every event, so ingestion systems can sort by severity while humans read the
+// level name. A nil scale uses OTelSeverityNumbers, levels missing from the
+// scale get no severity_number.
+func WithSeverityNumber(scale map[zerolog.Level]int) Option {
+	if scale == nil {
+		scale = OTelSeverityNumbers
+	}
+
+	return func(o *options) {
+		registerHookField("severity_number")
+		o.hooks = append(o.hooks, zerolog.HookFunc(func(e *zerolog.Event, level zerolog.Level, msg string) {
+			if n, ok := scale[level]; ok {
+				e.Int("severity_number", n)
+			}
+		}))
+	}
+}
@@ -0,0 +1,43 @@
+package zerolog_wrapper
+
+import (
+	"reflect"
+	"strconv"
+
+	"github.com/rs/zerolog"
+)
+
+// SliceSample adds at most max elements of the slice or array items as an
+// array under key. When items holds more, the array ends with a
+// "...and N more" entry. Struct elements are logged like Struct logs them,
+// a max of 0 or less keeps all elements. Values other than slices and arrays
+// are added as they are.
+//
+// Use it with the Func method of an event:
+//
+//	log.Info().Func(log.SliceSample("orders", orders, 10)).Msg("orders pending")
+//	// Output: {"level":"info","orders":[{"id":1},...,{"id":10},"...and 32 more"],"message":"orders pending"}
+func SliceSample(key string, items interface{}, max int) func(e *zerolog.Event) {
+	return func(e *zerolog.Event) {
+		rv := reflect.ValueOf(items)
+		if rv.Kind() != reflect.Slice && rv.Kind() != reflect.Array {
+			e.Interface(key, items)
+			return
+		}
+
+		n := rv.Len()
+		if max <= 0 || max > n {
+			max = n
+		}
+
+		arr := zerolog.Arr()
+		for i := 0; i < max; i++ {
+			arr.RawJSON(marshalRedacted(rv.Index(i), nil, 0))
+		}
+		if n > max {
+			arr.Str("...and " + strconv.Itoa(n-max) + " more")
+		}
+
+		e.Array(key, arr)
+	}
+}
@@ -0,0 +1,230 @@
+//go:build go1.21
+
+// Package sloghandler provides a log/slog handler writing through zerolog_wrapper,
+// so code using slog shares the output, level and context of the wrapper.
+//
+// It is kept apart from zerolog_wrapper so the main package doesn't require Go 1.21.
+//
+//	log.InitLog(log.InfoLevel, "prod")
+//	slog.SetDefault(slog.New(sloghandler.New()))
+//
+//	slog.Info("hello world", "foo", "bar")
+//	// Output: {"level":"info","time":1494567715,"foo":"bar","message":"hello world"}
+package sloghandler
+
+import (
+	"context"
+	"log/slog"
+	"runtime"
+
+	zerolog_wrapper "github.com/ashokrajar/zerolog_wrapper"
+	"github.com/rs/zerolog"
+)
+
+// Handler is a slog.Handler writing records as zerolog events.
+type Handler struct {
+	logger func(ctx context.Context) *zerolog.Logger
+	goas   []groupOrAttrs
+}
+
+// groupOrAttrs holds either a group name or attributes added through WithGroup and WithAttrs.
+type groupOrAttrs struct {
+	group string
+	attrs []slog.Attr
+}
+
+// New returns a handler writing through the global logger. The logger of
+// each record comes from zerolog_wrapper.FromContext, so levels set by
+// ContextWithLevel and loggers stored by WithContext apply to slog calls
+// made with a context, eg: slog.InfoContext.
+func New() *Handler {
+	return &Handler{
+		logger: func(ctx context.Context) *zerolog.Logger {
+			if ctx == nil {
+				ctx = context.Background()
+			}
+			return zerolog_wrapper.FromContext(ctx)
+		},
+	}
+}
+
+// NewForInstance returns a handler writing through i.
+func NewForInstance(i *zerolog_wrapper.Instance) *Handler {
+	return &Handler{
+		logger: func(context.Context) *zerolog.Logger {
+			l := i.GetLogger()
+			return &l
+		},
+	}
+}
+
+// Level maps a slog level to the wrapper's levels: below debug is trace,
+// then every range of four steps is debug, info, warn and error.
+func Level(level slog.Level) zerolog.Level {
+	switch {
+	case level < slog.LevelDebug:
+		return zerolog.TraceLevel
+	case level < slog.LevelInfo:
+		return zerolog.DebugLevel
+	case level < slog.LevelWarn:
+		return zerolog.InfoLevel
+	case level < slog.LevelError:
+		return zerolog.WarnLevel
+	default:
+		return zerolog.ErrorLevel
+	}
+}
+
+// Enabled reports whether the logger of ctx logs events of level.
+func (h *Handler) Enabled(ctx context.Context, level slog.Level) bool {
+	zl := Level(level)
+
+	return zl >= h.logger(ctx).GetLevel() && zl >= zerolog.GlobalLevel()
+}
+
+// Handle writes r as a zerolog event. Its time is left to the logger, which
+// adds its own time field. The caller field, when the logger adds one, is the
+// code which logged r through slog.
+func (h *Handler) Handle(ctx context.Context, r slog.Record) error {
+	e := h.logger(ctx).WithLevel(Level(r.Level))
+	if e == nil {
+		return nil
+	}
+
+	appendGroups(e, h.goas, r)
+	e.CallerSkipFrame(callerSkip(r.PC)).Msg(r.Message)
+
+	return nil
+}
+
+// callerSkip returns the number of frames between Handle and the caller at
+// pc, which are the frames of slog. When pc isn't found, eg: because it is
+// zero or the record was handled later, Handle itself is the caller.
+func callerSkip(pc uintptr) int {
+	if pc == 0 {
+		return 0
+	}
+
+	var pcs [16]uintptr
+	// skip runtime.Callers and callerSkip, so pcs starts at Handle
+	n := runtime.Callers(2, pcs[:])
+	for skip, frame := range pcs[:n] {
+		if frame == pc {
+			return skip
+		}
+	}
+
+	return 0
+}
+
+// WithAttrs returns a handler adding attrs to every record.
+func (h *Handler) WithAttrs(attrs []slog.Attr) slog.Handler {
+	if len(attrs) == 0 {
+		return h
+	}
+
+	return h.with(groupOrAttrs{attrs: attrs})
+}
+
+// WithGroup returns a handler nesting the attributes added later under name.
+func (h *Handler) WithGroup(name string) slog.Handler {
+	if name == "" {
+		return h
+	}
+
+	return h.with(groupOrAttrs{group: name})
+}
+
+func (h *Handler) with(goa groupOrAttrs) *Handler {
+	goas := make([]groupOrAttrs, 0, len(h.goas)+1)
+	goas = append(goas, h.goas...)
+
+	return &Handler{logger: h.logger, goas: append(goas, goa)}
+}
+
+// appendGroups adds the attributes of goas and r to e, opening a nested
+// object for each group.
+func appendGroups(e *zerolog.Event, goas []groupOrAttrs, r slog.Record) {
+	for n, goa := range goas {
+		if goa.group != "" {
+			// groups without any attributes are left out
+			if !hasAttrs(goas[n+1:], r) {
+				return
+			}
+			sub := zerolog.Dict()
+			appendGroups(sub, goas[n+1:], r)
+			e.Dict(goa.group, sub)
+			return
+		}
+		for _, a := range goa.attrs {
+			appendAttr(e, a)
+		}
+	}
+
+	r.Attrs(func(a slog.Attr) bool {
+		appendAttr(e, a)
+		return true
+	})
+}
+
+// hasAttrs reports whether goas or r hold any attribute.
+func hasAttrs(goas []groupOrAttrs, r slog.Record) bool {
+	if r.NumAttrs() > 0 {
+		return true
+	}
+	for _, goa := range goas {
+		if len(goa.attrs) > 0 {
+			return true
+		}
+	}
+
+	return false
+}
+
+// appendAttr adds a as a field of e.
+func appendAttr(e *zerolog.Event, a slog.Attr) {
+	a.Value = a.Value.Resolve()
+	if a.Equal(slog.Attr{}) {
+		return
+	}
+
+	switch a.Value.Kind() {
+	case slog.KindString:
+		e.Str(a.Key, a.Value.String())
+	case slog.KindInt64:
+		e.Int64(a.Key, a.Value.Int64())
+	case slog.KindUint64:
+		e.Uint64(a.Key, a.Value.Uint64())
+	case slog.KindFloat64:
+		e.Float64(a.Key, a.Value.Float64())
+	case slog.KindBool:
+		e.Bool(a.Key, a.Value.Bool())
+	case slog.KindDuration:
+		e.Dur(a.Key, a.Value.Duration())
+	case slog.KindTime:
+		e.Time(a.Key, a.Value.Time())
+	case slog.KindGroup:
+		attrs := a.Value.Group()
+		if len(attrs) == 0 {
+			return
+		}
+		// groups without a key are inlined
+		if a.Key == "" {
+			for _, ga := range attrs {
+				appendAttr(e, ga)
+			}
+			return
+		}
+		sub := zerolog.Dict()
+		for _, ga := range attrs {
+			appendAttr(sub, ga)
+		}
+		e.Dict(a.Key, sub)
+	default:
+		if err, ok := a.Value.Any().(error); ok {
+			e.AnErr(a.Key, err)
+			return
+		}
+		e.Interface(a.Key, a.Value.Any())
+	}
+}
@@ -0,0 +1,119 @@
+//go:build go1.21
+
+package sloghandler
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"log/slog"
+	"strings"
+	"testing"
+	"testing/slogtest"
+
+	zerolog_wrapper "github.com/ashokrajar/zerolog_wrapper"
+	"github.com/rs/zerolog"
+)
+
+func newInstance(t *testing.T, level zerolog_wrapper.LogLevel, buf *bytes.Buffer, opts ...zerolog_wrapper.Option) *zerolog_wrapper.Instance {
+	t.Helper()
+
+	i, err := zerolog_wrapper.New(level, zerolog_wrapper.Prod, append([]zerolog_wrapper.Option{
+		zerolog_wrapper.DisableHostIP(), zerolog_wrapper.WithOutput(buf),
+	}, opts...)...)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return i
+}
+
+func TestSlogtest(t *testing.T) {
+	var buf bytes.Buffer
+	h := NewForInstance(newInstance(t, zerolog_wrapper.TraceLevel, &buf))
+
+	results := func() []map[string]any {
+		var ms []map[string]any
+		for _, line := range bytes.Split(bytes.TrimSpace(buf.Bytes()), []byte("\n")) {
+			var m map[string]any
+			if err := json.Unmarshal(line, &m); err != nil {
+				t.Fatalf("%v: %s", err, line)
+			}
+			// slogtest expects slog's key for the message
+			m[slog.MessageKey] = m[zerolog.MessageFieldName]
+			delete(m, zerolog.MessageFieldName)
+			ms = append(ms, m)
+		}
+		return ms
+	}
+
+	err := slogtest.TestHandler(h, results)
+	var joined interface{ Unwrap() []error }
+	if !errors.As(err, &joined) {
+		if err != nil {
+			t.Fatal(err)
+		}
+		return
+	}
+	for _, err := range joined.Unwrap() {
+		// the logger adds its own timestamp to every event, see Handle
+		if strings.Contains(err.Error(), "zero Record.Time") {
+			continue
+		}
+		t.Error(err)
+	}
+}
+
+func TestLevel(t *testing.T) {
+	tests := []struct {
+		level slog.Level
+		want  zerolog.Level
+	}{
+		{slog.LevelDebug - 4, zerolog.TraceLevel},
+		{slog.LevelDebug - 1, zerolog.TraceLevel},
+		{slog.LevelDebug, zerolog.DebugLevel},
+		{slog.LevelInfo - 1, zerolog.DebugLevel},
+		{slog.LevelInfo, zerolog.InfoLevel},
+		{slog.LevelInfo + 2, zerolog.InfoLevel},
+		{slog.LevelWarn, zerolog.WarnLevel},
+		{slog.LevelError - 1, zerolog.WarnLevel},
+		{slog.LevelError, zerolog.ErrorLevel},
+		{slog.LevelError + 8, zerolog.ErrorLevel},
+	}
+
+	for _, tt := range tests {
+		if got := Level(tt.level); got != tt.want {
+			t.Errorf("Level(%s) = %s, want %s", tt.level, got, tt.want)
+		}
+	}
+}
+
+func TestEnabled(t *testing.T) {
+	var buf bytes.Buffer
+	logger := slog.New(NewForInstance(newInstance(t, zerolog_wrapper.WarnLevel, &buf)))
+
+	logger.Info("dropped")
+	logger.Warn("kept")
+
+	if strings.Contains(buf.String(), "dropped") || !strings.Contains(buf.String(), `"level":"warn"`) {
+		t.Errorf("unexpected output: %s", buf.String())
+	}
+}
+
+func TestCaller(t *testing.T) {
+	var buf bytes.Buffer
+	logger := slog.New(NewForInstance(newInstance(t, zerolog_wrapper.TraceLevel, &buf)))
+
+	logger.Info("with caller")
+	logger.With("k", "v").WithGroup("g").Warn("through with", "a", 1)
+	logger.LogAttrs(nil, slog.LevelError, "through LogAttrs")
+
+	for _, line := range bytes.Split(bytes.TrimSpace(buf.Bytes()), []byte("\n")) {
+		var event map[string]any
+		if err := json.Unmarshal(line, &event); err != nil {
+			t.Fatal(err)
+		}
+		if caller, _ := event[zerolog.CallerFieldName].(string); !strings.Contains(caller, "sloghandler_test.go") {
+			t.Errorf("caller = %q, want the slog call site", caller)
+		}
+	}
+}
@@ -0,0 +1,91 @@
+package zerolog_wrapper
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/rs/zerolog"
+)
+
+type spanKey struct{}
+
+// Span times an operation through log events, see StartSpan.
+type Span struct {
+	id     string
+	mu     sync.Mutex
+	logger zerolog.Logger
+	start  time.Time
+	fields map[string]interface{}
+	ended  bool
+}
+
+// StartSpan logs the start of the operation name at debug level and returns
+// the Span to end it with, plus a copy of ctx carrying the span.
+//
+// Every span gets a span_id. A span started from a ctx carrying another span
+// also gets that span's ID as parent_span_id, so nested spans can be put back
+// together. Events logged through FromContext on the returned ctx carry the
+// span_id of the innermost span as well.
+//
+// eg:
+//
+//	ctx, span := log.StartSpan(ctx, "load_user")
+//	defer span.End()
+func StartSpan(ctx context.Context, name string) (context.Context, *Span) {
+	id := newID()
+	c := contextLogger(ctx).With().
+		Str("span_name", name).
+		Str("span_id", id)
+	if parent, ok := spanFromContext(ctx); ok {
+		c = c.Str("parent_span_id", parent.id)
+	}
+
+	span := &Span{
+		id:     id,
+		logger: c.Logger(),
+		start:  time.Now(),
+	}
+	// the caller is the code starting the span
+	span.logger.Debug().CallerSkipFrame(1).Msg("span started")
+
+	return context.WithValue(ctx, spanKey{}, span), span
+}
+
+// spanFromContext returns the innermost span stored in ctx, if any.
+func spanFromContext(ctx context.Context) (*Span, bool) {
+	span, ok := ctx.Value(spanKey{}).(*Span)
+
+	return span, ok
+}
+
+// AddField adds a field to the event logged by End.
+func (s *Span) AddField(key string, value interface{}) *Span {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.fields == nil {
+		s.fields = map[string]interface{}{}
+	}
+	s.fields[key] = value
+
+	return s
+}
+
+// End logs the end of the span at info level with its duration and the
+// fields added through AddField. Only the first call logs.
+func (s *Span) End() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.ended {
+		return
+	}
+	s.ended = true
+
+	s.logger.Info().
+		Fields(s.fields).
+		Dur("duration", time.Since(s.start)).
+		CallerSkipFrame(1).
+		Msg("span ended")
+}
@@ -0,0 +1,114 @@
+package zerolog_wrapper
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"strings"
+	"testing"
+
+	"github.com/rs/zerolog"
+)
+
+// useTraceStd swaps the global logger for one writing every level, with caller, to the returned buffer.
+func useTraceStd(t *testing.T) *bytes.Buffer {
+	t.Helper()
+
+	var buf bytes.Buffer
+	l, err := New(TraceLevel, Prod, DisableHostIP(), WithOutput(&buf))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	previous := std
+	std = l
+	t.Cleanup(func() { std = previous })
+
+	return &buf
+}
+
+// spanIDs returns the span_id values of every line of buf, in order.
+func spanIDs(t *testing.T, buf *bytes.Buffer) [][]string {
+	t.Helper()
+
+	var ids [][]string
+	for _, line := range bytes.Split(bytes.TrimSpace(buf.Bytes()), []byte("\n")) {
+		fields, err := decodeFields(line)
+		if err != nil {
+			t.Fatalf("%v: %s", err, line)
+		}
+		var own []string
+		for _, field := range fields {
+			if field.key == "span_id" {
+				var id string
+				_ = json.Unmarshal(field.value, &id)
+				own = append(own, id)
+			}
+		}
+		ids = append(ids, own)
+	}
+	return ids
+}
+
+func TestFromContextSpanIDOnce(t *testing.T) {
+	buf := useTraceStd(t)
+
+	ctx, span := StartSpan(context.Background(), "outer")
+	// storing the logger of the span's ctx must not repeat its span_id
+	ctx = WithContext(ctx, FromContext(ctx).With().Str("k", "v").Logger())
+	FromContext(ctx).Info().Msg("in outer")
+
+	FromContext(ctx).Info().Msg("again")
+	span.End()
+
+	ids := spanIDs(t, buf)
+	if len(ids) != 4 {
+		t.Fatalf("got %d events, want 4: %s", len(ids), buf)
+	}
+	for i, own := range ids {
+		if len(own) != 1 || own[0] != span.id {
+			t.Errorf("event %d has span_id %v, want only %s", i, own, span.id)
+		}
+	}
+}
+
+func TestNestedSpan(t *testing.T) {
+	buf := useTraceStd(t)
+
+	ctx, span := StartSpan(context.Background(), "outer")
+	inner, innerSpan := StartSpan(ctx, "inner")
+	FromContext(inner).Info().Msg("in inner")
+	innerSpan.End()
+	span.End()
+
+	ids := spanIDs(t, buf)
+	want := []string{span.id, innerSpan.id, innerSpan.id, innerSpan.id, span.id}
+	if len(ids) != len(want) {
+		t.Fatalf("got %d events, want %d: %s", len(ids), len(want), buf)
+	}
+	for i, own := range ids {
+		if len(own) != 1 || own[0] != want[i] {
+			t.Errorf("event %d has span_id %v, want only %s", i, own, want[i])
+		}
+	}
+	if !strings.Contains(buf.String(), `"parent_span_id":"`+span.id+`"`) {
+		t.Errorf("inner span lacks parent_span_id: %s", buf)
+	}
+}
+
+func TestSpanCaller(t *testing.T) {
+	buf := useTraceStd(t)
+
+	_, span := StartSpan(context.Background(), "op")
+	span.End()
+
+	for _, line := range bytes.Split(bytes.TrimSpace(buf.Bytes()), []byte("\n")) {
+		var event map[string]interface{}
+		if err := json.Unmarshal(line, &event); err != nil {
+			t.Fatal(err)
+		}
+		if caller, _ := event[zerolog.CallerFieldName].(string); !strings.Contains(caller, "span_test.go") {
+			t.Errorf("%v: caller = %q, want the caller of the span", event["message"], caller)
+		}
+	}
+}
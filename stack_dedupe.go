@@ -0,0 +1,89 @@
+package zerolog_wrapper
+
+import (
+	"encoding/json"
+	"hash/fnv"
+	"sync"
+	"time"
+
+	"github.com/rs/zerolog"
+)
+
+// WithStackDedupe logs the stack of a recurring error only on its first
+// occurrence within window. Later events with the same error and the same
+// top stack frame drop their stack field and carry "stack_omitted":true
+// instead, so repeated errors stay informative without each of them
+// repeating the full stack.
+func WithStackDedupe(window time.Duration) Option {
+	return func(o *options) {
+		o.transforms = append(o.transforms, newStackDeduper(window).transform)
+	}
+}
+
+// stackDeduper remembers when the stack of each error signature was last logged.
+type stackDeduper struct {
+	mu     sync.Mutex
+	window time.Duration
+	seen   map[uint64]time.Time
+}
+
+func newStackDeduper(window time.Duration) *stackDeduper {
+	return &stackDeduper{window: window, seen: make(map[uint64]time.Time)}
+}
+
+func (d *stackDeduper) transform(fields []jsonField) []jsonField {
+	stack := -1
+	h := fnv.New64a()
+	for i, field := range fields {
+		switch field.key {
+		case zerolog.ErrorStackFieldName:
+			stack = i
+			h.Write([]byte(field.key))
+			h.Write(topFrame(field.value))
+		case zerolog.ErrorFieldName:
+			h.Write([]byte(field.key))
+			h.Write(field.value)
+		}
+	}
+	if stack < 0 || d.logStack(h.Sum64(), time.Now()) {
+		return fields
+	}
+
+	fields = append(fields[:stack], fields[stack+1:]...)
+
+	return append(fields, jsonField{key: "stack_omitted", value: []byte("true")})
+}
+
+// topFrame returns the first frame of stack when it is an array of frames,
+// as written by WithErrorStacks and zerolog's pkgerrors marshaler, or the
+// whole stack otherwise.
+func topFrame(stack json.RawMessage) json.RawMessage {
+	var frames []json.RawMessage
+	if err := json.Unmarshal(stack, &frames); err != nil || len(frames) == 0 {
+		return stack
+	}
+
+	return frames[0]
+}
+
+// logStack reports whether the stack of signature is due to be logged at now.
+func (d *stackDeduper) logStack(signature uint64, now time.Time) bool {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	if last, ok := d.seen[signature]; ok && now.Sub(last) < d.window {
+		return false
+	}
+
+	// drop expired signatures now and then so the map doesn't grow without bound
+	if len(d.seen) >= 1024 {
+		for s, last := range d.seen {
+			if now.Sub(last) >= d.window {
+				delete(d.seen, s)
+			}
+		}
+	}
+	d.seen[signature] = now
+
+	return true
+}
@@ -0,0 +1,74 @@
+package zerolog_wrapper
+
+import (
+	"bytes"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/rs/zerolog"
+)
+
+func TestWithStackDedupe(t *testing.T) {
+	var buf bytes.Buffer
+	l, err := New(InfoLevel, Prod, DisableHostIP(), WithOutput(&buf), WithErrorStacks(), WithStackDedupe(time.Hour))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	boom := errors.New("boom")
+	logged := func(msg string, err error) bool {
+		buf.Reset()
+		l.Error().Err(err).Msg(msg)
+		return bytes.Contains(buf.Bytes(), []byte(`"stack":`))
+	}
+
+	for i, tt := range []struct {
+		msg   string
+		err   error
+		stack bool
+	}{
+		{"first", boom, true},
+		// the message isn't part of the signature, eg: when it carries an ID
+		{"again", boom, false},
+		{"other error", errors.New("other"), true},
+	} {
+		if got := logged(tt.msg, tt.err); got != tt.stack {
+			t.Errorf("%d: stack logged = %v, want %v: %s", i, got, tt.stack, buf.String())
+		}
+	}
+
+}
+
+func TestWithStackDedupeTopFrame(t *testing.T) {
+	top := "a"
+	previous := zerolog.ErrorStackMarshaler
+	zerolog.ErrorStackMarshaler = func(err error) interface{} {
+		return []map[string]string{{"func": top}, {"func": "main"}}
+	}
+	t.Cleanup(func() { zerolog.ErrorStackMarshaler = previous })
+
+	var buf bytes.Buffer
+	l, err := New(InfoLevel, Prod, DisableHostIP(), WithOutput(&buf), WithStackDedupe(time.Hour))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	boom := errors.New("boom")
+	for i, tt := range []struct {
+		top   string
+		stack bool
+	}{
+		{"a", true},
+		{"a", false},
+		// the same error raised elsewhere
+		{"b", true},
+	} {
+		top = tt.top
+		buf.Reset()
+		l.Error().Stack().Err(boom).Msg("failed")
+		if got := bytes.Contains(buf.Bytes(), []byte(`"stack":`)); got != tt.stack {
+			t.Errorf("%d: stack logged = %v, want %v: %s", i, got, tt.stack, buf.String())
+		}
+	}
+}
@@ -0,0 +1,40 @@
+package zerolog_wrapper
+
+import (
+	"runtime"
+
+	"github.com/rs/zerolog"
+)
+
+// maxStackDepth caps the frames counted by StackDepth.
+const maxStackDepth = 4096
+
+// StackDepth returns the number of frames on the stack of the calling
+// goroutine, eg: to spot runaway recursion before it overflows the stack.
+// Deeper stacks are reported as 4096.
+func StackDepth() int {
+	pcs := make([]uintptr, maxStackDepth)
+
+	// skip runtime.Callers and StackDepth itself
+	return runtime.Callers(2, pcs)
+}
+
+// WithStackDepth adds the stack depth of the logging goroutine as stack_depth
+// to every event of level or higher. The depth includes the frames
+// of the logger itself.
+func WithStackDepth(level LogLevel) Option {
+	return func(o *options) {
+		registerHookField("stack_depth")
+		o.hooks = append(o.hooks, stackDepthHook{level: toZerologLevel(level)})
+	}
+}
+
+type stackDepthHook struct {
+	level zerolog.Level
+}
+
+func (h stackDepthHook) Run(e *zerolog.Event, level zerolog.Level, msg string) {
+	if level >= h.level && level <= zerolog.PanicLevel {
+		e.Int("stack_depth", StackDepth())
+	}
+}
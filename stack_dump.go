@@ -0,0 +1,71 @@
+package zerolog_wrapper
+
+import (
+	"os"
+	"os/signal"
+	"runtime"
+	"sync"
+	"syscall"
+)
+
+// maxStackDumpSize caps the size of the dump logged by DumpStacksOnSignal.
+const maxStackDumpSize = 64 << 20
+
+// DumpStacksOnSignal makes each of the given signals (eg: syscall.SIGUSR2)
+// log the stacks of all goroutines at error level under goroutine_dump,
+// together with their number as goroutines, eg: to capture the state of a
+// deadlocked service through the normal log pipeline without a terminal.
+// Unlike the default handling of SIGQUIT the process keeps running. Without
+// signals it handles SIGQUIT. Dumps larger than 64 MiB are truncated.
+//
+// The returned function removes the signal handler.
+func DumpStacksOnSignal(sig ...os.Signal) (stop func()) {
+	if len(sig) == 0 {
+		// signal.Notify without signals would relay every signal, eg: swallow SIGTERM
+		sig = []os.Signal{syscall.SIGQUIT}
+	}
+
+	sigs := make(chan os.Signal, 1)
+	done := make(chan struct{})
+	var wg sync.WaitGroup
+
+	signal.Notify(sigs, sig...)
+
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		for {
+			select {
+			case <-done:
+				return
+			case s := <-sigs:
+				Error().
+					Str("signal", s.String()).
+					Int("goroutines", runtime.NumGoroutine()).
+					Bytes("goroutine_dump", allStacks()).
+					Msg("goroutine dump")
+			}
+		}
+	}()
+
+	var stopOnce sync.Once
+	return func() {
+		stopOnce.Do(func() {
+			signal.Stop(sigs)
+			close(done)
+			wg.Wait()
+		})
+	}
+}
+
+// allStacks returns the stacks of all goroutines, growing the buffer until they fit.
+func allStacks() []byte {
+	buf := make([]byte, 64<<10)
+	for {
+		n := runtime.Stack(buf, true)
+		if n < len(buf) || len(buf) >= maxStackDumpSize {
+			return buf[:n]
+		}
+		buf = make([]byte, 2*len(buf))
+	}
+}
@@ -0,0 +1,106 @@
+package zerolog_wrapper
+
+import (
+	"sync/atomic"
+	"time"
+
+	"github.com/rs/zerolog"
+)
+
+// LevelStats holds the event counters of a single level.
+type LevelStats struct {
+	// Emitted counts the events handed to the output.
+	Emitted uint64
+	// SampledOut counts the events skipped by sampling.
+	SampledOut uint64
+	// Dropped counts the events lost by the output, eg: an asynchronous writer running full.
+	Dropped uint64
+}
+
+type levelCounters struct {
+	emitted, sampledOut, dropped atomic.Uint64
+}
+
+// eventCounters holds one entry per level from trace to panic.
+type eventCounters [zerolog.PanicLevel - zerolog.TraceLevel + 1]levelCounters
+
+// forLevel returns the counters of level, or nil for events without a level.
+func (ec *eventCounters) forLevel(level zerolog.Level) *levelCounters {
+	if level < zerolog.TraceLevel || level > zerolog.PanicLevel {
+		return nil
+	}
+
+	return &ec[level-zerolog.TraceLevel]
+}
+
+func (ec *eventCounters) countEmitted(level zerolog.Level) {
+	if c := ec.forLevel(level); c != nil {
+		c.emitted.Add(1)
+	}
+}
+
+func (ec *eventCounters) countSampledOut(level zerolog.Level) {
+	if c := ec.forLevel(level); c != nil {
+		c.sampledOut.Add(1)
+	}
+}
+
+func (ec *eventCounters) countDropped(level zerolog.Level) {
+	if c := ec.forLevel(level); c != nil {
+		c.dropped.Add(1)
+	}
+}
+
+// Stats returns the event counters of each level since the process started.
+func Stats() map[LogLevel]LevelStats {
+	return std.Stats()
+}
+
+// Stats returns the event counters of each level of the instance.
+func (i *Instance) Stats() map[LogLevel]LevelStats {
+	stats := make(map[LogLevel]LevelStats, len(i.counters))
+	for level := zerolog.TraceLevel; level <= zerolog.PanicLevel; level++ {
+		c := i.counters.forLevel(level)
+		stats[LogLevel(level.String())] = LevelStats{
+			Emitted:    c.emitted.Load(),
+			SampledOut: c.sampledOut.Load(),
+			Dropped:    c.dropped.Load(),
+		}
+	}
+
+	return stats
+}
+
+// StartStatsReporter logs the counters returned by Stats at info level every
+// interval until the returned function is called, one object per level from
+// trace to panic. A zero or negative interval reports nothing.
+func StartStatsReporter(interval time.Duration) (stop func()) {
+	return every(interval, func() {
+		stats := Stats()
+		e := Info()
+		for level := zerolog.TraceLevel; level <= zerolog.PanicLevel; level++ {
+			s := stats[LogLevel(level.String())]
+			e = e.Dict(level.String(), zerolog.Dict().
+				Uint64("emitted", s.Emitted).
+				Uint64("sampled_out", s.SampledOut).
+				Uint64("dropped", s.Dropped))
+		}
+		e.Msg("log stats")
+	})
+}
+
+// statsWriter counts every event passing through it as emitted.
+type statsWriter struct {
+	out      zerolog.LevelWriter
+	counters *eventCounters
+}
+
+func (w statsWriter) Write(p []byte) (int, error) {
+	return w.WriteLevel(zerolog.NoLevel, p)
+}
+
+func (w statsWriter) WriteLevel(level zerolog.Level, p []byte) (int, error) {
+	w.counters.countEmitted(level)
+
+	return w.out.WriteLevel(level, p)
+}
@@ -0,0 +1,44 @@
+package zerolog_wrapper
+
+import (
+	"bytes"
+	"testing"
+	"time"
+)
+
+func TestStartStatsReporter(t *testing.T) {
+	var buf lockedBuffer
+	useStdOutput(t, &buf)
+	Warn().Msg("counted")
+
+	stop := StartStatsReporter(time.Millisecond)
+	waitFor(t, func() bool { return bytes.Contains(buf.Bytes(), []byte("log stats")) })
+	stop()
+
+	lines := bytes.Split(buf.Bytes(), []byte("\n"))
+	if len(lines) < 2 || !bytes.Contains(lines[1], []byte("log stats")) {
+		t.Fatalf("no stats reported: %s", buf.Bytes())
+	}
+
+	var levels []string
+	for _, key := range fieldKeys(lines[1]) {
+		switch key {
+		case "trace", "debug", "info", "warn", "error", "fatal", "panic":
+			levels = append(levels, key)
+		}
+	}
+	want := []string{"trace", "debug", "info", "warn", "error", "fatal", "panic"}
+	if len(levels) != len(want) {
+		t.Fatalf("levels = %v, want %v", levels, want)
+	}
+	for n := range want {
+		if levels[n] != want[n] {
+			t.Fatalf("levels = %v, want %v", levels, want)
+		}
+	}
+}
+
+func TestStartStatsReporterNonPositiveInterval(t *testing.T) {
+	useStd(t)
+	StartStatsReporter(-time.Second)()
+}
@@ -0,0 +1,51 @@
+package zerolog_wrapper
+
+import "sync/atomic"
+
+// statusLevelMapper holds the func(code int) LogLevel set by SetLevelForStatus.
+var statusLevelMapper atomic.Value
+
+// SetLevelForStatus replaces the mapping of HTTP status codes onto levels
+// used by LevelForStatus, and so by Middleware and LoggingTransport, eg: for
+// teams treating 404 as expected. An empty level returned by levelFor falls
+// back to DefaultLevelForStatus, a nil levelFor restores the default.
+//
+// eg:
+//
+//	log.SetLevelForStatus(func(code int) log.LogLevel {
+//		if code == http.StatusNotFound {
+//			return log.InfoLevel
+//		}
+//		return ""
+//	})
+func SetLevelForStatus(levelFor func(code int) LogLevel) {
+	if levelFor == nil {
+		levelFor = DefaultLevelForStatus
+	}
+	statusLevelMapper.Store(levelFor)
+}
+
+// DefaultLevelForStatus is the default mapping of LevelForStatus: 5xx
+// responses are errors, 4xx responses warnings and everything else info.
+func DefaultLevelForStatus(code int) LogLevel {
+	switch {
+	case code >= 500:
+		return ErrorLevel
+	case code >= 400:
+		return WarnLevel
+	default:
+		return InfoLevel
+	}
+}
+
+// LevelForStatus returns the level an HTTP response with status code is logged at,
+// from the mapping set by SetLevelForStatus.
+func LevelForStatus(code int) LogLevel {
+	if levelFor, ok := statusLevelMapper.Load().(func(int) LogLevel); ok {
+		if level := levelFor(code); level != "" {
+			return level
+		}
+	}
+
+	return DefaultLevelForStatus(code)
+}
@@ -0,0 +1,88 @@
+package zerolog_wrapper
+
+import (
+	"io"
+	stdlog "log"
+	"runtime"
+	"strings"
+	"sync"
+
+	"github.com/rs/zerolog"
+)
+
+var (
+	stdLogMu       sync.Mutex
+	stdLogRedirect bool
+	stdLogOutput   io.Writer
+	stdLogFlags    int
+)
+
+// RedirectStdLog sends everything written through the default logger of the
+// standard library log package to the global logger at level, so third party
+// code using log.Printf ends up in the structured logs. The log flags are
+// cleared since the events carry their own time and caller.
+// RestoreStdLog undoes it.
+//
+// eg:
+//
+//	log.RedirectStdLog(log.WarnLevel)
+//	defer log.RestoreStdLog()
+func RedirectStdLog(level LogLevel) {
+	stdLogMu.Lock()
+	defer stdLogMu.Unlock()
+
+	if !stdLogRedirect {
+		stdLogOutput = stdlog.Writer()
+		stdLogFlags = stdlog.Flags()
+		stdLogRedirect = true
+	}
+
+	stdlog.SetFlags(0)
+	stdlog.SetOutput(stdLogWriter{level: toZerologLevel(level)})
+}
+
+// RestoreStdLog restores the output and flags the standard library logger had before RedirectStdLog.
+func RestoreStdLog() {
+	stdLogMu.Lock()
+	defer stdLogMu.Unlock()
+
+	if !stdLogRedirect {
+		return
+	}
+
+	stdlog.SetOutput(stdLogOutput)
+	stdlog.SetFlags(stdLogFlags)
+	stdLogRedirect = false
+}
+
+// stdLogWriter logs every line written by the standard library logger as an event.
+type stdLogWriter struct {
+	level zerolog.Level
+}
+
+func (w stdLogWriter) Write(p []byte) (int, error) {
+	l := current()
+	l.WithLevel(w.level).
+		CallerSkipFrame(stdLogCallerSkip()).
+		Msg(strings.TrimSuffix(string(p), "\n"))
+
+	return len(p), nil
+}
+
+// stdLogCallerSkip returns the number of frames from stdLogWriter.Write up
+// to the code calling the standard library logger, eg: log.Printf and the
+// Logger method it calls.
+func stdLogCallerSkip() int {
+	var pcs [8]uintptr
+	// skip runtime.Callers, stdLogCallerSkip and Write
+	frames := runtime.CallersFrames(pcs[:runtime.Callers(3, pcs[:])])
+
+	skip := 1
+	for {
+		frame, more := frames.Next()
+		if !strings.HasPrefix(frame.Function, "log.") || !more {
+			return skip
+		}
+		skip++
+	}
+}
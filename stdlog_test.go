@@ -0,0 +1,40 @@
+package zerolog_wrapper
+
+import (
+	"bytes"
+	"encoding/json"
+	stdlog "log"
+	"strings"
+	"testing"
+
+	"github.com/rs/zerolog"
+)
+
+func TestRedirectStdLog(t *testing.T) {
+	buf := useTraceStd(t)
+
+	RedirectStdLog(WarnLevel)
+	defer RestoreStdLog()
+
+	stdlog.Printf("from %s", "Printf")
+	stdlog.Println("from Println")
+	stdlog.Default().Print("from a Logger method")
+	_, _ = stdlog.Writer().Write([]byte("from Write\n"))
+
+	lines := bytes.Split(bytes.TrimSpace(buf.Bytes()), []byte("\n"))
+	if len(lines) != 4 {
+		t.Fatalf("got %d events, want 4: %s", len(lines), buf)
+	}
+	for _, line := range lines {
+		var event map[string]interface{}
+		if err := json.Unmarshal(line, &event); err != nil {
+			t.Fatal(err)
+		}
+		if event[zerolog.LevelFieldName] != "warn" {
+			t.Errorf("%v: level = %v, want warn", event["message"], event[zerolog.LevelFieldName])
+		}
+		if caller, _ := event[zerolog.CallerFieldName].(string); !strings.Contains(caller, "stdlog_test.go") {
+			t.Errorf("%v: caller = %q, want the call of the standard library logger", event["message"], caller)
+		}
+	}
+}
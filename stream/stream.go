@@ -0,0 +1,294 @@
+// Package stream provides a writer publishing log events to a message broker
+// topic, eg: a Kafka topic or a NATS subject, so other systems can consume
+// the logs as a stream without a separate shipper.
+//
+// It is kept apart from zerolog_wrapper and doesn't depend on any broker
+// client. Publishing goes through a small Publisher adapter instead:
+//
+//	type kafkaPublisher struct{ w *kafka.Writer }
+//
+//	func (p kafkaPublisher) Publish(ctx context.Context, batch []stream.Message) error {
+//		msgs := make([]kafka.Message, len(batch))
+//		for i, m := range batch {
+//			msgs[i] = kafka.Message{Key: m.Key, Value: m.Value}
+//		}
+//		return p.w.WriteMessages(ctx, msgs...)
+//	}
+//
+//	w := stream.NewWriter(kafkaPublisher{w: kw})
+//	defer w.Close()
+//	log.InitLog(log.InfoLevel, "prod", log.WithOutput(w))
+package stream
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"os"
+	"sync"
+	"time"
+)
+
+// Message is a single event to publish.
+type Message struct {
+	// Key is the partition key, eg: the correlation ID of the event.
+	Key []byte
+	// Value is the JSON event.
+	Value []byte
+}
+
+// Publisher publishes a batch of messages to the configured topic or subject.
+type Publisher interface {
+	Publish(ctx context.Context, batch []Message) error
+}
+
+// Option configures a Writer.
+type Option func(*Writer)
+
+// WithBatchSize publishes once size events are collected, 100 by default.
+func WithBatchSize(size int) Option {
+	return func(w *Writer) {
+		w.batchSize = size
+	}
+}
+
+// WithFlushInterval publishes the events collected so far every interval, one
+// second by default. A zero or negative interval turns this off, events are
+// then published once a batch is full and on Close.
+func WithFlushInterval(interval time.Duration) Option {
+	return func(w *Writer) {
+		w.flushInterval = interval
+	}
+}
+
+// WithQueueSize sets how many events may wait for delivery, 1000 by default.
+// Events beyond that are written to the fallback instead.
+func WithQueueSize(size int) Option {
+	return func(w *Writer) {
+		w.queueSize = size
+	}
+}
+
+// WithPublishTimeout bounds each call to Publish, five seconds by default.
+func WithPublishTimeout(timeout time.Duration) Option {
+	return func(w *Writer) {
+		w.publishTimeout = timeout
+	}
+}
+
+// WithKeyField sets the event field used as partition key, correlation_id by
+// default. Events without it are published without a key.
+func WithKeyField(field string) Option {
+	return func(w *Writer) {
+		w.keyField = field
+	}
+}
+
+// WithFallback sets where events go when publishing fails or the queue is
+// full, os.Stderr by default.
+func WithFallback(fallback io.Writer) Option {
+	return func(w *Writer) {
+		w.fallback = fallback
+	}
+}
+
+// Writer publishes each JSON event written to it through a Publisher, in
+// batches and from a background goroutine, so logging never waits for the broker.
+type Writer struct {
+	pub            Publisher
+	batchSize      int
+	flushInterval  time.Duration
+	queueSize      int
+	publishTimeout time.Duration
+	keyField       string
+
+	fallbackMu sync.Mutex
+	fallback   io.Writer
+
+	mu      sync.RWMutex
+	closed  bool
+	queue   chan Message
+	flushes chan chan struct{}
+	done    chan struct{}
+}
+
+// NewWriter returns a Writer publishing through pub. Call Close before the
+// program exits to publish the events still queued.
+func NewWriter(pub Publisher, opts ...Option) *Writer {
+	w := &Writer{
+		pub:            pub,
+		batchSize:      100,
+		flushInterval:  time.Second,
+		queueSize:      1000,
+		publishTimeout: 5 * time.Second,
+		keyField:       "correlation_id",
+		fallback:       os.Stderr,
+	}
+	for _, opt := range opts {
+		opt(w)
+	}
+
+	w.queue = make(chan Message, w.queueSize)
+	w.flushes = make(chan chan struct{})
+	w.done = make(chan struct{})
+	go w.run()
+
+	return w
+}
+
+// Write queues the event p for publishing.
+func (w *Writer) Write(p []byte) (int, error) {
+	w.mu.RLock()
+	defer w.mu.RUnlock()
+
+	// zerolog reuses the event buffer once the write returns
+	m := Message{Key: w.key(p), Value: append([]byte(nil), p...)}
+	if w.closed {
+		w.writeFallback([]Message{m})
+		return len(p), nil
+	}
+
+	select {
+	case w.queue <- m:
+	default:
+		w.writeFallback([]Message{m})
+	}
+
+	return len(p), nil
+}
+
+// Close publishes the queued events and stops the background goroutine.
+// Events written afterwards go to the fallback.
+func (w *Writer) Close() error {
+	w.mu.Lock()
+	if w.closed {
+		w.mu.Unlock()
+		return nil
+	}
+	w.closed = true
+	close(w.queue)
+	w.mu.Unlock()
+
+	<-w.done
+
+	return nil
+}
+
+// Flush publishes the events written so far and waits for it until ctx is
+// done, each publish is bounded by WithPublishTimeout as well. Unlike Close,
+// the Writer keeps publishing afterwards. zerolog_wrapper calls it on Flush,
+// Shutdown, Critical and before a fatal event exits.
+func (w *Writer) Flush(ctx context.Context) error {
+	flushed := make(chan struct{})
+
+	w.mu.RLock()
+	if w.closed {
+		w.mu.RUnlock()
+		return nil
+	}
+	select {
+	case w.flushes <- flushed:
+	case <-ctx.Done():
+		w.mu.RUnlock()
+		return ctx.Err()
+	}
+	w.mu.RUnlock()
+
+	select {
+	case <-flushed:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+// key returns the value of the key field of the event p, nil if it has none.
+func (w *Writer) key(p []byte) []byte {
+	if w.keyField == "" {
+		return nil
+	}
+
+	var fields map[string]json.RawMessage
+	if err := json.Unmarshal(p, &fields); err != nil {
+		return nil
+	}
+	raw, ok := fields[w.keyField]
+	if !ok {
+		return nil
+	}
+
+	var s string
+	if err := json.Unmarshal(raw, &s); err == nil {
+		return []byte(s)
+	}
+
+	return raw
+}
+
+func (w *Writer) run() {
+	defer close(w.done)
+
+	// a nil channel never fires, leaving publishing to the batch size
+	var tick <-chan time.Time
+	if w.flushInterval > 0 {
+		ticker := time.NewTicker(w.flushInterval)
+		defer ticker.Stop()
+		tick = ticker.C
+	}
+
+	batch := make([]Message, 0, w.batchSize)
+	for {
+		select {
+		case m, ok := <-w.queue:
+			if !ok {
+				w.publish(batch)
+				return
+			}
+			batch = append(batch, m)
+			if len(batch) >= w.batchSize {
+				w.publish(batch)
+				batch = make([]Message, 0, w.batchSize)
+			}
+		case <-tick:
+			if len(batch) > 0 {
+				w.publish(batch)
+				batch = make([]Message, 0, w.batchSize)
+			}
+		case flushed := <-w.flushes:
+			// the events written before Flush are queued already
+			for n := len(w.queue); n > 0; n-- {
+				batch = append(batch, <-w.queue)
+				if len(batch) >= w.batchSize {
+					w.publish(batch)
+					batch = make([]Message, 0, w.batchSize)
+				}
+			}
+			w.publish(batch)
+			batch = make([]Message, 0, w.batchSize)
+			close(flushed)
+		}
+	}
+}
+
+// publish publishes batch, falling back to writing it out on failure.
+func (w *Writer) publish(batch []Message) {
+	if len(batch) == 0 {
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), w.publishTimeout)
+	defer cancel()
+
+	if err := w.pub.Publish(ctx, batch); err != nil {
+		w.writeFallback(batch)
+	}
+}
+
+func (w *Writer) writeFallback(batch []Message) {
+	w.fallbackMu.Lock()
+	defer w.fallbackMu.Unlock()
+
+	for _, m := range batch {
+		_, _ = w.fallback.Write(m.Value)
+	}
+}
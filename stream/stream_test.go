@@ -0,0 +1,115 @@
+package stream
+
+import (
+	"context"
+	"errors"
+	"io"
+	"sync"
+	"testing"
+	"time"
+)
+
+// recordingPublisher keeps the batches published through it.
+type recordingPublisher struct {
+	mu      sync.Mutex
+	batches [][]Message
+}
+
+func (p *recordingPublisher) Publish(ctx context.Context, batch []Message) error {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	p.batches = append(p.batches, batch)
+	return nil
+}
+
+func TestWriterWithoutFlushInterval(t *testing.T) {
+	for _, interval := range []time.Duration{0, -time.Second} {
+		pub := &recordingPublisher{}
+		w := NewWriter(pub, WithFlushInterval(interval), WithBatchSize(2))
+
+		for _, event := range []string{`{"n":1}`, `{"n":2}`, `{"n":3}`} {
+			if _, err := w.Write([]byte(event)); err != nil {
+				t.Fatal(err)
+			}
+		}
+		if err := w.Close(); err != nil {
+			t.Fatal(err)
+		}
+
+		if len(pub.batches) != 2 || len(pub.batches[0]) != 2 || len(pub.batches[1]) != 1 {
+			t.Errorf("interval %s: published %v, want a full batch and the rest on Close", interval, pub.batches)
+		}
+	}
+}
+
+func TestWriterFlush(t *testing.T) {
+	pub := &recordingPublisher{}
+	w := NewWriter(pub, WithFlushInterval(0), WithBatchSize(2))
+	defer w.Close()
+
+	for _, event := range []string{`{"n":1}`, `{"n":2}`, `{"n":3}`} {
+		if _, err := w.Write([]byte(event)); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := w.Flush(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+
+	pub.mu.Lock()
+	published := 0
+	for _, batch := range pub.batches {
+		published += len(batch)
+	}
+	pub.mu.Unlock()
+	if published != 3 {
+		t.Errorf("published %d events before Flush returned, want 3", published)
+	}
+
+	// the writer keeps publishing after Flush
+	if _, err := w.Write([]byte(`{"n":4}`)); err != nil {
+		t.Fatal(err)
+	}
+	if err := w.Flush(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+	pub.mu.Lock()
+	defer pub.mu.Unlock()
+	if last := pub.batches[len(pub.batches)-1]; len(last) != 1 || string(last[0].Value) != `{"n":4}` {
+		t.Errorf("last batch %v, want the event written after the first Flush", last)
+	}
+}
+
+// blockingPublisher blocks until its context is done.
+type blockingPublisher struct{}
+
+func (blockingPublisher) Publish(ctx context.Context, batch []Message) error {
+	<-ctx.Done()
+	return ctx.Err()
+}
+
+func TestWriterFlushBounded(t *testing.T) {
+	w := NewWriter(blockingPublisher{}, WithFlushInterval(0), WithPublishTimeout(time.Second), WithFallback(io.Discard))
+	defer w.Close()
+
+	if _, err := w.Write([]byte(`{"n":1}`)); err != nil {
+		t.Fatal(err)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+	if err := w.Flush(ctx); !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("Flush = %v, want the deadline of its ctx", err)
+	}
+}
+
+func TestWriterFlushAfterClose(t *testing.T) {
+	w := NewWriter(&recordingPublisher{})
+	if err := w.Close(); err != nil {
+		t.Fatal(err)
+	}
+	if err := w.Flush(context.Background()); err != nil {
+		t.Errorf("Flush after Close = %v, want nil", err)
+	}
+}
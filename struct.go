@@ -0,0 +1,415 @@
+package zerolog_wrapper
+
+import (
+	"bytes"
+	"encoding"
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+
+	"github.com/rs/zerolog"
+)
+
+// redactedValue replaces the value of redacted fields.
+const redactedValue = "[REDACTED]"
+
+// cycleValue replaces the value of pointers back to a struct being logged.
+const cycleValue = "[CYCLE]"
+
+// maxStructDepth caps how deep Struct follows nested structs.
+const maxStructDepth = 32
+
+var (
+	jsonMarshalerType = reflect.TypeOf((*json.Marshaler)(nil)).Elem()
+	textMarshalerType = reflect.TypeOf((*encoding.TextMarshaler)(nil)).Elem()
+)
+
+// Struct adds the exported fields of the struct v as a nested object under key.
+// The fields are chosen like encoding/json does: names follow the json tags,
+// fields tagged json:"-" are skipped, omitempty skips false, 0, nil and
+// empty strings, slices and maps, and the fields of untagged embedded
+// structs are promoted. Only the string tag option is ignored. Fields tagged
+// log:"redact" are logged as "[REDACTED]", also in the structs
+// held by its fields, slices, maps and pointers. Pointers back to a struct
+// being logged are logged as "[CYCLE]" and values nested deeper than 32 levels
+// as null. Values other than structs are added as they are.
+//
+// Use it with the Func method of an event:
+//
+//	type Config struct {
+//		Host     string `json:"host"`
+//		Password string `json:"password" log:"redact"`
+//	}
+//
+//	log.Info().Func(log.Struct("config", cfg)).Msg("loaded config")
+//	// Output: {"level":"info","config":{"host":"db","password":"[REDACTED]"},"message":"loaded config"}
+func Struct(key string, v interface{}) func(e *zerolog.Event) {
+	return func(e *zerolog.Event) {
+		rv := reflect.ValueOf(v)
+		var path []uintptr
+		for rv.Kind() == reflect.Pointer && !rv.IsNil() && !containsPointer(path, rv.Pointer()) {
+			path = append(path, rv.Pointer())
+			rv = rv.Elem()
+		}
+		if rv.Kind() != reflect.Struct {
+			e.Interface(key, v)
+			return
+		}
+
+		e.Object(key, structObject{v: rv, path: path, depth: 1})
+	}
+}
+
+// structObject marshals the exported fields of a struct value.
+type structObject struct {
+	v reflect.Value
+	// path holds the addresses of the values pointed to on the way to v
+	path  []uintptr
+	depth int
+}
+
+func (s structObject) MarshalZerologObject(e *zerolog.Event) {
+	for _, field := range s.fields() {
+		e.RawJSON(field.key, field.value)
+	}
+}
+
+// fields returns the exported fields of the struct, marshaled by marshalRedacted.
+func (s structObject) fields() []jsonField {
+	var fields []jsonField
+	for _, field := range structFields(s.v.Type()) {
+		value, path, ok := fieldByIndex(s.v, field.index, s.path)
+		if !ok {
+			continue
+		}
+
+		if field.omitEmpty && isEmptyJSON(value) {
+			continue
+		}
+		if field.redact {
+			fields = append(fields, jsonField{key: field.name, value: redactedJSON})
+			continue
+		}
+
+		fields = append(fields, jsonField{key: field.name, value: marshalRedacted(value, path, s.depth)})
+	}
+
+	return fields
+}
+
+// structField is a field marshaled by Struct, possibly promoted from an embedded struct.
+type structField struct {
+	name      string
+	index     []int
+	omitEmpty bool
+	redact    bool
+	// tagged is set when the name comes from a json tag
+	tagged bool
+}
+
+// structFieldsCache holds the structFields of each struct type.
+var structFieldsCache sync.Map
+
+// structFields returns the fields encoding/json marshals for the struct type
+// t, in its order: the fields of untagged embedded structs are promoted
+// and of several fields with the same name only the least nested one is
+// kept, or the tagged one of those, or none of them.
+func structFields(t reflect.Type) []structField {
+	if fields, ok := structFieldsCache.Load(t); ok {
+		return fields.([]structField)
+	}
+
+	var all []structField
+	collectFields(t, nil, false, map[reflect.Type]bool{}, &all)
+
+	// the dominant field of each name, see encoding/json
+	byName := make(map[string][]structField)
+	for _, field := range all {
+		byName[field.name] = append(byName[field.name], field)
+	}
+	var fields []structField
+	for _, field := range all {
+		if dominant, ok := dominantField(byName[field.name]); ok && sameIndex(dominant.index, field.index) {
+			fields = append(fields, field)
+		}
+	}
+
+	structFieldsCache.Store(t, fields)
+
+	return fields
+}
+
+// collectFields appends the fields of the struct type t to fields, in index
+// order and descending into untagged embedded structs. index leads to t,
+// redact is set when t is embedded through a redacted field.
+func collectFields(t reflect.Type, index []int, redact bool, visiting map[reflect.Type]bool, fields *[]structField) {
+	// an embedded struct reached again through itself adds nothing
+	if visiting[t] {
+		return
+	}
+	visiting[t] = true
+	defer delete(visiting, t)
+
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+		ft := field.Type
+		if ft.Kind() == reflect.Pointer {
+			ft = ft.Elem()
+		}
+		if field.Anonymous {
+			// unexported embedded structs still promote their exported fields
+			if !field.IsExported() && ft.Kind() != reflect.Struct {
+				continue
+			}
+		} else if !field.IsExported() {
+			continue
+		}
+
+		tag := field.Tag.Get("json")
+		if tag == "-" {
+			continue
+		}
+		name, opts, _ := strings.Cut(tag, ",")
+		fieldIndex := append(index[:len(index):len(index)], i)
+		fieldRedact := redact || field.Tag.Get("log") == "redact"
+
+		if field.Anonymous && name == "" && ft.Kind() == reflect.Struct {
+			collectFields(ft, fieldIndex, fieldRedact, visiting, fields)
+			continue
+		}
+
+		tagged := name != ""
+		if !tagged {
+			name = field.Name
+		}
+		*fields = append(*fields, structField{
+			name:      name,
+			index:     fieldIndex,
+			omitEmpty: strings.Contains(","+opts+",", ",omitempty,"),
+			redact:    fieldRedact,
+			tagged:    tagged,
+		})
+	}
+}
+
+// dominantField returns the field encoding/json marshals of the fields
+// sharing a name, reporting false when they cancel each other out.
+func dominantField(fields []structField) (structField, bool) {
+	depth := len(fields[0].index)
+	for _, field := range fields {
+		if len(field.index) < depth {
+			depth = len(field.index)
+		}
+	}
+
+	var dominant []structField
+	tagged := 0
+	for _, field := range fields {
+		if len(field.index) == depth {
+			dominant = append(dominant, field)
+			if field.tagged {
+				tagged++
+			}
+		}
+	}
+	if len(dominant) == 1 {
+		return dominant[0], true
+	}
+	if tagged == 1 {
+		for _, field := range dominant {
+			if field.tagged {
+				return field, true
+			}
+		}
+	}
+
+	return structField{}, false
+}
+
+func sameIndex(a, b []int) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for n := range a {
+		if a[n] != b[n] {
+			return false
+		}
+	}
+
+	return true
+}
+
+// fieldByIndex returns the field of v at index, following pointers to
+// embedded structs and adding their addresses to path. It reports false
+// when the field is held by a nil embedded pointer.
+func fieldByIndex(v reflect.Value, index []int, path []uintptr) (reflect.Value, []uintptr, bool) {
+	for n, i := range index {
+		if n > 0 && v.Kind() == reflect.Pointer {
+			if v.IsNil() {
+				return reflect.Value{}, nil, false
+			}
+			path = append(path[:len(path):len(path)], v.Pointer())
+			v = v.Elem()
+		}
+		v = v.Field(i)
+	}
+
+	return v, path, true
+}
+
+// isEmptyJSON reports whether v is empty as omitempty means it in encoding/json.
+func isEmptyJSON(v reflect.Value) bool {
+	switch v.Kind() {
+	case reflect.Array, reflect.Map, reflect.Slice, reflect.String:
+		return v.Len() == 0
+	case reflect.Bool,
+		reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr,
+		reflect.Float32, reflect.Float64,
+		reflect.Interface, reflect.Pointer:
+		return v.IsZero()
+	}
+
+	return false
+}
+
+var (
+	nullJSON     = json.RawMessage("null")
+	redactedJSON = json.RawMessage(strconv.Quote(redactedValue))
+	cycleJSON    = json.RawMessage(strconv.Quote(cycleValue))
+)
+
+// marshalRedacted returns v as JSON like encoding/json does, except that the
+// structs in v, wherever they are held, are marshaled like Struct marshals them.
+// depth is how deep v is nested, path holds the addresses of the values pointed to on the way to v.
+func marshalRedacted(v reflect.Value, path []uintptr, depth int) json.RawMessage {
+	for v.Kind() == reflect.Pointer || v.Kind() == reflect.Interface {
+		if v.IsNil() {
+			return nullJSON
+		}
+		if marshaler, ok := ownMarshaler(v); ok {
+			return marshalJSON(marshaler)
+		}
+		if v.Kind() == reflect.Pointer {
+			if containsPointer(path, v.Pointer()) {
+				return cycleJSON
+			}
+			path = append(path[:len(path):len(path)], v.Pointer())
+		}
+		v = v.Elem()
+	}
+	if !v.IsValid() {
+		return nullJSON
+	}
+	if marshaler, ok := ownMarshaler(v); ok {
+		return marshalJSON(marshaler)
+	}
+
+	switch v.Kind() {
+	case reflect.Struct, reflect.Map, reflect.Slice, reflect.Array:
+		if depth >= maxStructDepth {
+			return nullJSON
+		}
+	}
+
+	switch v.Kind() {
+	case reflect.Struct:
+		return encodeObject(structObject{v: v, path: path, depth: depth + 1}.fields())
+	case reflect.Map:
+		if v.IsNil() {
+			return nullJSON
+		}
+		fields := make([]jsonField, 0, v.Len())
+		iter := v.MapRange()
+		for iter.Next() {
+			fields = append(fields, jsonField{key: mapKey(iter.Key()), value: marshalRedacted(iter.Value(), path, depth+1)})
+		}
+		sort.Slice(fields, func(a, b int) bool { return fields[a].key < fields[b].key })
+		return encodeObject(fields)
+	case reflect.Slice:
+		// byte slices are base64 encoded like encoding/json does
+		if v.IsNil() || v.Type().Elem().Kind() == reflect.Uint8 {
+			return marshalJSON(v.Interface())
+		}
+		fallthrough
+	case reflect.Array:
+		var buf bytes.Buffer
+		buf.WriteByte('[')
+		for n := 0; n < v.Len(); n++ {
+			if n > 0 {
+				buf.WriteByte(',')
+			}
+			buf.Write(marshalRedacted(v.Index(n), path, depth+1))
+		}
+		buf.WriteByte(']')
+		return buf.Bytes()
+	}
+
+	return marshalJSON(v.Interface())
+}
+
+// ownMarshaler returns what encoding/json marshals v with when v implements
+// json.Marshaler or encoding.TextMarshaler, directly or through its address.
+func ownMarshaler(v reflect.Value) (interface{}, bool) {
+	t := v.Type()
+	if t.Implements(jsonMarshalerType) || t.Implements(textMarshalerType) {
+		return v.Interface(), true
+	}
+	if v.CanAddr() && (reflect.PointerTo(t).Implements(jsonMarshalerType) || reflect.PointerTo(t).Implements(textMarshalerType)) {
+		return v.Addr().Interface(), true
+	}
+
+	return nil, false
+}
+
+// mapKey returns the name encoding/json gives the map key k.
+func mapKey(k reflect.Value) string {
+	if k.Kind() == reflect.String {
+		return k.String()
+	}
+	if tm, ok := k.Interface().(encoding.TextMarshaler); ok {
+		if text, err := tm.MarshalText(); err == nil {
+			return string(text)
+		}
+	}
+
+	switch k.Kind() {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return strconv.FormatInt(k.Int(), 10)
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+		return strconv.FormatUint(k.Uint(), 10)
+	}
+
+	return fmt.Sprint(k.Interface())
+}
+
+// marshalJSON marshals v with encoding/json, turning a failure into a string
+// the way zerolog does for Interface fields.
+func marshalJSON(v interface{}) json.RawMessage {
+	b, err := json.Marshal(v)
+	if err != nil {
+		b, _ = json.Marshal(fmt.Sprintf("marshaling error: %v", err))
+	}
+
+	return b
+}
+
+// encodeObject serializes fields into a JSON object.
+func encodeObject(fields []jsonField) json.RawMessage {
+	return bytes.TrimSuffix(encodeFields(fields), []byte("\n"))
+}
+
+// containsPointer reports whether path holds the address p.
+func containsPointer(path []uintptr, p uintptr) bool {
+	for _, addr := range path {
+		if addr == p {
+			return true
+		}
+	}
+
+	return false
+}
@@ -0,0 +1,143 @@
+package zerolog_wrapper
+
+import (
+	"bytes"
+	"encoding/json"
+	"strings"
+	"testing"
+)
+
+type structConfig struct {
+	Host     string `json:"host"`
+	Password string `json:"password" log:"redact"`
+	Secret   string `json:"-"`
+	Port     int    `json:"port,omitempty"`
+}
+
+type structNode struct {
+	Name string      `json:"name"`
+	Next *structNode `json:"next,omitempty"`
+}
+
+func logStruct(t *testing.T, v interface{}) []byte {
+	t.Helper()
+
+	var buf bytes.Buffer
+	l, err := New(InfoLevel, Prod, DisableHostIP(), WithOutput(&buf))
+	if err != nil {
+		t.Fatal(err)
+	}
+	l.Info().Func(Struct("v", v)).Msg("struct")
+
+	if !json.Valid(buf.Bytes()) {
+		t.Fatalf("invalid JSON: %s", buf.String())
+	}
+	return buf.Bytes()
+}
+
+func TestStruct(t *testing.T) {
+	out := logStruct(t, &structConfig{Host: "db", Password: "hunter2", Secret: "s"})
+	if !bytes.Contains(out, []byte(`"v":{"host":"db","password":"[REDACTED]"}`)) {
+		t.Errorf("unexpected output: %s", out)
+	}
+}
+
+func TestStructCycle(t *testing.T) {
+	self := &structNode{Name: "self"}
+	self.Next = self
+	if out := logStruct(t, self); !bytes.Contains(out, []byte(`"v":{"name":"self","next":"[CYCLE]"}`)) {
+		t.Errorf("unexpected output: %s", out)
+	}
+
+	a := &structNode{Name: "a"}
+	a.Next = &structNode{Name: "b", Next: a}
+	if out := logStruct(t, a); !bytes.Contains(out, []byte(`"v":{"name":"a","next":{"name":"b","next":"[CYCLE]"}}`)) {
+		t.Errorf("unexpected output: %s", out)
+	}
+}
+
+func TestStructMaxDepth(t *testing.T) {
+	var head *structNode
+	for n := 0; n < 2*maxStructDepth; n++ {
+		head = &structNode{Name: "node", Next: head}
+	}
+
+	out := logStruct(t, head)
+	if got := strings.Count(string(out), `"name":"node"`); got != maxStructDepth {
+		t.Errorf("got %d nested structs, want %d", got, maxStructDepth)
+	}
+}
+
+type structNested struct {
+	List  []structConfig           `json:"list"`
+	Map   map[string]*structConfig `json:"map"`
+	Bytes []byte                   `json:"bytes"`
+}
+
+func TestStructRedactsInsideContainers(t *testing.T) {
+	out := logStruct(t, structNested{
+		List:  []structConfig{{Host: "a", Password: "p1"}},
+		Map:   map[string]*structConfig{"b": {Host: "b", Password: "p2"}, "nil": nil},
+		Bytes: []byte("hi"),
+	})
+
+	want := `"v":{"list":[{"host":"a","password":"[REDACTED]"}],"map":{"b":{"host":"b","password":"[REDACTED]"},"nil":null},"bytes":"aGk="}`
+	if !bytes.Contains(out, []byte(want)) {
+		t.Errorf("got %s, want %s", out, want)
+	}
+}
+
+type structBase struct {
+	ID   int    `json:"id"`
+	Name string `json:"name"`
+}
+
+type structAudit struct {
+	Name  string `json:"name"`
+	Token string `json:"token" log:"redact"`
+}
+
+type structHidden struct {
+	Hidden string `json:"hidden"`
+}
+
+type structEmbedding struct {
+	structBase
+	*structAudit
+	structHidden
+	Name  string      `json:"title"`
+	Inner structBase  `json:"inner"`
+	Tags  []string    `json:"tags,omitempty"`
+	Attrs map[int]int `json:"attrs,omitempty"`
+	Empty structBase  `json:"empty,omitempty"`
+	Zero  int         `json:",omitempty"`
+}
+
+func TestStructMatchesEncodingJSON(t *testing.T) {
+	for name, v := range map[string]structEmbedding{
+		"nil embedded pointer": {structBase: structBase{ID: 1, Name: "base"}, Name: "outer", Tags: []string{}, Attrs: map[int]int{}},
+		"embedded pointer":     {structBase: structBase{ID: 1}, structAudit: &structAudit{Name: "audit"}, structHidden: structHidden{Hidden: "h"}, Tags: []string{"a"}},
+	} {
+		out := logStruct(t, v)
+
+		var event struct {
+			V map[string]interface{} `json:"v"`
+		}
+		if err := json.Unmarshal(out, &event); err != nil {
+			t.Fatal(err)
+		}
+
+		var want map[string]interface{}
+		b, _ := json.Marshal(v)
+		_ = json.Unmarshal(b, &want)
+		if _, ok := want["token"]; ok {
+			want["token"] = redactedValue
+		}
+
+		got, _ := json.Marshal(event.V)
+		wanted, _ := json.Marshal(want)
+		if !bytes.Equal(got, wanted) {
+			t.Errorf("%s: got %s, want %s", name, got, wanted)
+		}
+	}
+}
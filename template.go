@@ -0,0 +1,56 @@
+package zerolog_wrapper
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/rs/zerolog"
+)
+
+// Msgt sends e with the message rendered from template, filling each {name}
+// placeholder with args[name]. The raw template is kept as message_template
+// and each arg is added as a field, so aggregation tools can group events by
+// template even when the rendered message varies. Placeholders without an
+// arg are left as they are.
+//
+// eg:
+//
+//	log.Msgt(log.Info(), "user {user} logged in from {ip}", map[string]interface{}{"user": "42", "ip": ip})
+//	// Output: {"level":"info","ip":"10.0.0.1","user":"42","message_template":"user {user} logged in from {ip}","message":"user 42 logged in from 10.0.0.1"}
+func Msgt(e *zerolog.Event, template string, args map[string]interface{}) {
+	if e == nil {
+		return
+	}
+
+	e.Fields(args).
+		Str("message_template", template).
+		CallerSkipFrame(1).
+		Msg(renderTemplate(template, args))
+}
+
+// renderTemplate fills the {name} placeholders of template from args.
+func renderTemplate(template string, args map[string]interface{}) string {
+	var b strings.Builder
+	for {
+		start := strings.IndexByte(template, '{')
+		if start < 0 {
+			break
+		}
+		end := strings.IndexByte(template[start:], '}')
+		if end < 0 {
+			break
+		}
+		end += start
+
+		b.WriteString(template[:start])
+		if value, ok := args[template[start+1:end]]; ok {
+			fmt.Fprint(&b, value)
+		} else {
+			b.WriteString(template[start : end+1])
+		}
+		template = template[end+1:]
+	}
+	b.WriteString(template)
+
+	return b.String()
+}
@@ -0,0 +1,54 @@
+package zerolog_wrapper
+
+import (
+	"bytes"
+	"encoding/json"
+	"strings"
+	"testing"
+)
+
+func TestMsgt(t *testing.T) {
+	var buf bytes.Buffer
+	l, err := New(InfoLevel, Prod, DisableHostIP(), WithOutput(&buf), WithMinCallerLevel(InfoLevel))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	Msgt(l.Info(), "user {user} logged in from {ip}", map[string]interface{}{"user": "42", "ip": "10.0.0.1"})
+
+	var event map[string]interface{}
+	if err := json.Unmarshal(buf.Bytes(), &event); err != nil {
+		t.Fatalf("%v: %s", err, buf.String())
+	}
+	if event["message"] != "user 42 logged in from 10.0.0.1" {
+		t.Errorf("message = %v", event["message"])
+	}
+	if event["message_template"] != "user {user} logged in from {ip}" {
+		t.Errorf("message_template = %v", event["message_template"])
+	}
+	if event["user"] != "42" || event["ip"] != "10.0.0.1" {
+		t.Errorf("args missing: %s", buf.String())
+	}
+	if caller, _ := event["caller"].(string); !strings.Contains(caller, "template_test.go") {
+		t.Errorf("caller = %q, want the call site of Msgt", caller)
+	}
+}
+
+func TestRenderTemplate(t *testing.T) {
+	tests := []struct {
+		template string
+		want     string
+	}{
+		{"no placeholders", "no placeholders"},
+		{"{a} and {b}", "1 and two"},
+		{"unknown {c} kept", "unknown {c} kept"},
+		{"unclosed {a", "unclosed {a"},
+	}
+
+	args := map[string]interface{}{"a": 1, "b": "two"}
+	for _, tt := range tests {
+		if got := renderTemplate(tt.template, args); got != tt.want {
+			t.Errorf("renderTemplate(%q) = %q, want %q", tt.template, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,40 @@
+package zerolog_wrapper
+
+import (
+	"time"
+
+	"github.com/rs/zerolog"
+)
+
+// TimeFormat is the layout of the values written by Time and TimeRange,
+// independent of zerolog.TimeFieldFormat.
+var TimeFormat = time.RFC3339
+
+// Time adds t under key formatted with TimeFormat, so schedule times read the
+// same in every output whatever the global time format is.
+//
+// Use it with the Func method of an event:
+//
+//	log.Info().Func(log.Time("next_run", next)).Msg("job scheduled")
+//	// Output: {"level":"info","next_run":"2024-05-01T02:00:00Z","message":"job scheduled"}
+func Time(key string, t time.Time) func(e *zerolog.Event) {
+	return func(e *zerolog.Event) {
+		e.Str(key, t.Format(TimeFormat))
+	}
+}
+
+// TimeRange adds the window from start to end under key as an object with
+// start and end formatted like Time and the duration between them.
+//
+// eg:
+//
+//	log.Info().Func(log.TimeRange("window", start, end)).Msg("maintenance planned")
+//	// Output: {"level":"info","window":{"start":"2024-05-01T02:00:00Z","end":"2024-05-01T03:30:00Z","duration":"1h30m0s"},"message":"maintenance planned"}
+func TimeRange(key string, start, end time.Time) func(e *zerolog.Event) {
+	return func(e *zerolog.Event) {
+		e.Dict(key, zerolog.Dict().
+			Str("start", start.Format(TimeFormat)).
+			Str("end", end.Format(TimeFormat)).
+			Str("duration", end.Sub(start).String()))
+	}
+}
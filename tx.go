@@ -0,0 +1,92 @@
+package zerolog_wrapper
+
+import (
+	"context"
+	"sync/atomic"
+	"time"
+
+	"github.com/rs/zerolog"
+)
+
+// TxLogger logs the lifecycle of a single database transaction, see BeginTx.
+type TxLogger struct {
+	// Logger carries the tx_id field of the transaction.
+	Logger zerolog.Logger
+
+	start      time.Time
+	statements atomic.Int64
+}
+
+// BeginTx logs the start of a transaction at debug level and returns the
+// TxLogger to log its statements and outcome through. The logger comes from
+// FromContext and carries a freshly generated tx_id, so every event of the
+// transaction can be correlated.
+//
+// eg:
+//
+//	txLog := log.BeginTx(ctx)
+//	start := time.Now()
+//	_, err := tx.ExecContext(ctx, query, args...)
+//	txLog.Statement(query, time.Since(start), err)
+//	if err != nil {
+//		txLog.Rollback(tx.Rollback(), err)
+//		return err
+//	}
+//	txLog.Commit(tx.Commit())
+func BeginTx(ctx context.Context) *TxLogger {
+	tx := &TxLogger{
+		Logger: FromContext(ctx).With().Str("tx_id", newID()).Logger(),
+		start:  time.Now(),
+	}
+	tx.Logger.Debug().Msg("transaction started")
+
+	return tx
+}
+
+// Statement logs a statement of the transaction with its duration, at debug
+// level or at error level when err is not nil.
+func (tx *TxLogger) Statement(query string, duration time.Duration, err error) {
+	tx.statements.Add(1)
+
+	level := zerolog.DebugLevel
+	if err != nil {
+		level = zerolog.ErrorLevel
+	}
+
+	tx.Logger.WithLevel(level).
+		Err(err).
+		Str("query", query).
+		Dur("duration", duration).
+		Msg("transaction statement")
+}
+
+// Commit logs the commit of the transaction with its total duration and
+// number of statements. A nil err is logged at info level, a failed commit
+// at error level.
+func (tx *TxLogger) Commit(err error) {
+	if err != nil {
+		tx.end(tx.Logger.Error().Err(err), "transaction commit failed")
+		return
+	}
+
+	tx.end(tx.Logger.Info(), "transaction committed")
+}
+
+// Rollback logs the rollback of the transaction at warn level with its total
+// duration and number of statements. err is the error returned by the
+// rollback itself, cause the error which made the transaction roll back,
+// either may be nil.
+func (tx *TxLogger) Rollback(err, cause error) {
+	level := zerolog.WarnLevel
+	if err != nil {
+		level = zerolog.ErrorLevel
+	}
+
+	tx.end(tx.Logger.WithLevel(level).Err(err).AnErr("cause", cause), "transaction rolled back")
+}
+
+func (tx *TxLogger) end(e *zerolog.Event, msg string) {
+	e.Int64("statements", tx.statements.Load()).
+		Dur("duration", time.Since(tx.start)).
+		Msg(msg)
+}
@@ -0,0 +1,59 @@
+package zerolog_wrapper
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestTxLogger(t *testing.T) {
+	buf := useTraceStd(t)
+
+	tx := BeginTx(context.Background())
+	tx.Statement("SELECT 1", time.Millisecond, nil)
+	tx.Statement("SELECT 2", time.Millisecond, errors.New("syntax"))
+	tx.Rollback(nil, errors.New("syntax"))
+	tx.Rollback(errors.New("conn closed"), nil)
+	tx.Commit(nil)
+
+	var events []map[string]interface{}
+	for _, line := range bytes.Split(bytes.TrimSpace(buf.Bytes()), []byte("\n")) {
+		var event map[string]interface{}
+		if err := json.Unmarshal(line, &event); err != nil {
+			t.Fatal(err)
+		}
+		events = append(events, event)
+	}
+
+	want := []struct {
+		level, message, err string
+	}{
+		{"debug", "transaction started", ""},
+		{"debug", "transaction statement", ""},
+		{"error", "transaction statement", "syntax"},
+		{"warn", "transaction rolled back", ""},
+		{"error", "transaction rolled back", "conn closed"},
+		{"info", "transaction committed", ""},
+	}
+	if len(events) != len(want) {
+		t.Fatalf("got %d events, want %d: %s", len(events), len(want), buf)
+	}
+	for i, w := range want {
+		e := events[i]
+		if e["level"] != w.level || e["message"] != w.message {
+			t.Errorf("event %d = %v, want %s %q", i, e, w.level, w.message)
+		}
+		if got, _ := e["error"].(string); got != w.err {
+			t.Errorf("event %d error = %q, want %q", i, got, w.err)
+		}
+		if e["tx_id"] == nil {
+			t.Errorf("event %d lacks tx_id", i)
+		}
+	}
+	if events[3]["cause"] != "syntax" || events[5]["statements"] != float64(2) {
+		t.Errorf("unexpected rollback or commit fields: %v %v", events[3], events[5])
+	}
+}
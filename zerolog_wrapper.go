@@ -20,6 +20,11 @@
 //	log.Info().Str("foo", "bar").Msg("hello world")
 //	// Output: {"time":1494567715,"level":"info","message":"hello world","foo":"bar"}
 //
+// Fields which are expensive to compute can be added lazily, they are only computed
+// when the event is enabled:
+//
+//	log.Debug().Func(log.Lazy("dump", func() interface{} { return expensive() })).Msg("state")
+//
 // # Updating the logger context
 //
 //	log.UpdateContext(func(c zerolog.Context) zerolog.Context {
@@ -28,13 +33,12 @@
 package zerolog_wrapper
 
 import (
-	"io"
+	"context"
+	"fmt"
 	"net"
 	"os"
 	"strconv"
-	"strings"
 	"sync"
-	"time"
 
 	"github.com/rs/zerolog"
 )
@@ -60,80 +64,117 @@ const (
 	Dev   Env = "dev"
 )
 
-var once sync.Once
-
-var log zerolog.Logger
+var (
+	// initMu guards initialized, set once the global logger was set up
+	initMu      sync.Mutex
+	initialized bool
+)
 
-// Get local address of the running system
+// Get local address of the running system, nil if it can't be determined
 func getLocalIP() net.IP {
 	conn, err := net.Dial("udp", "1.1.1.1:53")
 	if err != nil {
-		log.Fatal().Err(err)
+		return nil
 	}
 	defer conn.Close()
 
 	return conn.LocalAddr().(*net.UDPAddr).IP
 }
 
+// marshalCaller writes the caller field with a trimmed file name and the build version, if set.
+func marshalCaller(pc uintptr, file string, line int) string {
+	caller := trimCallerPath(file) + ":" + strconv.Itoa(line)
+	if version := currentBuildVersion(); version != "" {
+		caller += "@" + version
+	}
+
+	return caller
+}
+
 // InitLog initializes a global logger
-func InitLog(logLevelStr LogLevel, appEnv Env) {
-	once.Do(func() {
-		var logLevel zerolog.Level
-
-		switch logLevelStr {
-		case TraceLevel:
-			logLevel = zerolog.TraceLevel
-		case DebugLevel:
-			logLevel = zerolog.DebugLevel
-		case InfoLevel:
-			logLevel = zerolog.InfoLevel
-		case WarnLevel:
-			logLevel = zerolog.WarnLevel
-		case ErrorLevel:
-			logLevel = zerolog.ErrorLevel
-		case FatalLevel:
-			logLevel = zerolog.FatalLevel
-		case PanicLevel:
-			logLevel = zerolog.PanicLevel
-		default:
-			logLevel = zerolog.InfoLevel // default to INFO
-		}
+func InitLog(logLevelStr LogLevel, appEnv Env, opts ...Option) {
+	InitLogWithContext(context.Background(), logLevelStr, appEnv, opts...)
+}
+
+// InitLogWithContext initializes a global logger like InitLog, tying the
+// background work started for its options to ctx. Once ctx is canceled the
+// logger shuts down as if Shutdown was called: the periodic flushes of
+// WithBuffer stop, the events queued by WithAsync are written out and its
+// goroutine ends, a pending WithCloudMetadata lookup is canceled, every
+// buffering writer is flushed and the file of WithFile is closed. Outputs
+// given through WithOutput or WithOutputs are flushed when they can be, but
+// not closed.
+//
+// The workers of helpers returning their own stop function aren't tied to
+// ctx and run until that function is called: StartMemStatsReporter,
+// StartStatsReporter, WatchLevelFile, CycleLevelOnSignal and DumpStacksOnSignal.
+func InitLogWithContext(ctx context.Context, logLevelStr LogLevel, appEnv Env, opts ...Option) {
+	if err := initStd(ctx, logLevelStr, appEnv, newOptions(opts)); err == nil {
+		return
+	}
+
+	if err := initStd(ctx, logLevelStr, appEnv, newOptions(applicableOptions(opts))); err != nil {
+		fmt.Fprintf(os.Stderr, "zerolog_wrapper: %v, ignoring options\n", err)
+		_ = initStd(ctx, logLevelStr, appEnv, newOptions(nil))
+	}
+}
 
-		output := zerolog.MultiLevelWriter(os.Stderr)
+// applicableOptions returns opts without the options which can't be applied,
+// reporting each of them on stderr, so eg: an invalid buffer size doesn't
+// discard the output chosen by WithOutput or WithFile as well.
+func applicableOptions(opts []Option) []Option {
+	var kept []Option
+	var filePath string
+	for n, opt := range opts {
+		candidate := append(kept[:len(kept):len(kept)], opt)
+		o := newOptions(candidate)
 
-		// enforce TRACE and console output in development environment
-		if appEnv == Dev {
-			var consoleOutput io.Writer = zerolog.ConsoleWriter{
-				Out:        os.Stdout,
-				TimeFormat: time.RFC3339,
-			}
-			logLevel = zerolog.TraceLevel
-			output = zerolog.MultiLevelWriter(consoleOutput)
+		err := o.validate()
+		if err == nil && o.filePath != filePath {
+			err = checkFile(o.filePath)
+		}
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "zerolog_wrapper: %v, ignoring option %d\n", err, n+1)
+			continue
 		}
 
-		// Shorter file name in caller field
-		zerolog.CallerMarshalFunc = func(pc uintptr, file string, line int) string {
-			curDir, _ := os.Getwd()
-			shortPath := strings.TrimPrefix(file, curDir+"/")
+		kept = candidate
+		filePath = o.filePath
+	}
 
-			return shortPath + ":" + strconv.Itoa(line)
-		}
+	return kept
+}
 
-		log = zerolog.New(output).
-			Level(logLevel).
-			With().
-			Timestamp().
-			IPAddr("host_ip", getLocalIP()).
-			Logger()
+// initStd sets up the global logger from o, unless it was set up before.
+// Options which can't be applied leave the global logger untouched.
+func initStd(ctx context.Context, logLevelStr LogLevel, appEnv Env, o *options) error {
+	initMu.Lock()
+	defer initMu.Unlock()
 
-		if logLevelStr == TraceLevel || appEnv == Dev {
-			log = log.With().Caller().Logger()
-		}
+	if initialized {
+		return nil
+	}
+	if err := o.validate(); err != nil {
+		return err
+	}
+	if err := o.openFile(); err != nil {
+		return err
+	}
+	o.hooks = append(o.hooks, prefixHook{})
 
-	})
+	setZerologGlobals()
+	// set only here as it is process wide, see WithLevelNames
+	if o.levelNames != nil {
+		zerolog.LevelFieldMarshalFunc = levelNameMarshaler(o.levelNames)
+	}
+	std.setup(ctx, logLevelStr, appEnv, o)
+	initialized = true
+
+	return nil
 }
 
 // UpdateContext is a function that updates the internal logger's context.
+// It is safe for concurrent use.
 //
 // Parameters:
 // update: A function taking a zerolog.Context as input and then returns a zerolog.Context.
@@ -144,7 +185,35 @@ func InitLog(logLevelStr LogLevel, appEnv Env) {
 //		return c.Str("some_default_key", "some_default_value")
 //	})
 func UpdateContext(update func(c zerolog.Context) zerolog.Context) {
-	log.UpdateContext(update)
+	std.UpdateContext(update)
+}
+
+// UpdateContextBatch applies several context updates to the internal logger
+// at once, so no event is logged with only some of them applied.
+//
+// Like UpdateContext it is safe for concurrent use.
+//
+// eg:
+//
+//	log.UpdateContextBatch([]func(zerolog.Context) zerolog.Context{
+//		func(c zerolog.Context) zerolog.Context { return c.Str("service", "billing") },
+//		func(c zerolog.Context) zerolog.Context { return c.Str("version", version) },
+//	})
+func UpdateContextBatch(updates []func(c zerolog.Context) zerolog.Context) {
+	std.UpdateContextBatch(updates)
+}
+
+// updateContext updates the global logger's context regardless of FinalizeConfig.
+func updateContext(update func(c zerolog.Context) zerolog.Context) {
+	std.updateContext(update)
+}
+
+// FinalizeConfig locks the configuration of the global logger.
+//
+// Any later attempt to change it, eg: through UpdateContext, is ignored and logged as a warning.
+// Applications call this once their own setup is complete so library code can't alter it by accident.
+func FinalizeConfig() {
+	std.FinalizeConfig()
 }
 
 // GetLogger returns the global logger from the zerolog package.
@@ -153,54 +222,80 @@ func UpdateContext(update func(c zerolog.Context) zerolog.Context) {
 //
 //	The zerolog.Logger instance used for logging in the application.
 func GetLogger() zerolog.Logger {
-	return log
+	return current()
+}
+
+// currentEnv returns the environment the global logger was initialized for.
+func currentEnv() Env {
+	return std.currentEnv()
+}
+
+// current returns a copy of the global logger which is safe to use without holding a lock.
+func current() zerolog.Logger {
+	return std.current()
 }
 
 // Trace starts a new message with trace level.
 //
 // You must call Msg on the returned event in order to send the event.
 func Trace() *zerolog.Event {
-	return log.Trace()
+	return std.Trace()
 }
 
 // Debug starts a new message with debug level.
 //
 // You must call Msg on the returned event in order to send the event.
 func Debug() *zerolog.Event {
-	return log.Debug()
+	return std.Debug()
 }
 
 // Info starts a new message with info level.
 //
 // You must call Msg on the returned event in order to send the event.
 func Info() *zerolog.Event {
-	return log.Info()
+	return std.Info()
 }
 
 // Warn starts a new message with warn level.
 //
 // You must call Msg on the returned event in order to send the event.
 func Warn() *zerolog.Event {
-	return log.Warn()
+	return std.Warn()
 }
 
 // Error starts a new message with error level.
 //
 // You must call Msg on the returned event in order to send the event.
 func Error() *zerolog.Event {
-	return log.Error()
+	return std.Error()
 }
 
-// Fatal starts a new message with fatal level.
+// Fatal starts a new message with fatal level. The os.Exit(1) function
+// is called by the Msg method, which terminates the program immediately.
+// Before that, buffering writers are flushed so neither the fatal event nor
+// those logged before it are lost, see WithFatalFlushTimeout.
+//
+// In environments where SetFatalExit turned exiting off, the message is
+// logged at error level with would_exit set to true instead.
 //
 // You must call Msg on the returned event in order to send the event.
 func Fatal() *zerolog.Event {
-	return log.Fatal()
+	return std.Fatal()
 }
 
 // Panic starts a new message with panic level.
 //
 // You must call Msg on the returned event in order to send the event.
 func Panic() *zerolog.Event {
-	return log.Panic()
+	return std.Panic()
+}
+
+// WithLevel starts a new message with level chosen at runtime, eg: the level
+// named in a config row. Level names are matched case insensitively and an
+// unknown level logs at info level. Unlike Fatal and Panic, the fatal and
+// panic levels only set the level of the message, they neither exit nor panic.
+//
+// You must call Msg on the returned event in order to send the event.
+func WithLevel(level LogLevel) *zerolog.Event {
+	return std.WithLevel(level)
 }
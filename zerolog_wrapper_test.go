@@ -0,0 +1,91 @@
+package zerolog_wrapper
+
+import (
+	"bytes"
+	"context"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+// resetStd gives the test a global logger which isn't set up yet.
+func resetStd(t *testing.T) {
+	t.Helper()
+
+	initMu.Lock()
+	previous, wasInitialized := std, initialized
+	std, initialized = &Instance{}, false
+	initMu.Unlock()
+
+	t.Cleanup(func() {
+		initMu.Lock()
+		defer initMu.Unlock()
+		std, initialized = previous, wasInitialized
+	})
+}
+
+func TestInitLogSkipsOnlyInvalidOptions(t *testing.T) {
+	resetStd(t)
+
+	var buf bytes.Buffer
+	missing := filepath.Join(t.TempDir(), "missing", "app.log")
+	InitLog(InfoLevel, Prod,
+		DisableHostIP(),
+		WithOutput(&buf),
+		WithBuffer(-1, 0),
+		WithFile(missing),
+		WithAllowedFields(false, "kept"),
+	)
+
+	Info().Str("kept", "yes").Str("dropped", "yes").Msg("still here")
+
+	out := buf.String()
+	if !bytes.Contains(buf.Bytes(), []byte(`"message":"still here"`)) {
+		t.Fatalf("event didn't reach WithOutput: %q", out)
+	}
+	if bytes.Contains(buf.Bytes(), []byte(`"dropped"`)) {
+		t.Errorf("option after the invalid ones wasn't applied: %s", out)
+	}
+}
+
+func TestApplicableOptions(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "app.log")
+	missing := filepath.Join(t.TempDir(), "missing", "app.log")
+
+	kept := applicableOptions([]Option{
+		WithFile(path),
+		WithAsync(-1),
+		WithFile(missing),
+		WithGzip(100),
+		WithRecentLogs(10),
+	})
+	if len(kept) != 2 {
+		t.Fatalf("kept %d options, want 2", len(kept))
+	}
+
+	o := newOptions(kept)
+	if o.filePath != path || o.recentLogsSize != 10 {
+		t.Errorf("kept the wrong options: file %q, recent logs %d", o.filePath, o.recentLogsSize)
+	}
+}
+
+func TestInitLogWithContextShutsDownOnCancel(t *testing.T) {
+	resetStd(t)
+
+	var out lockedBuffer
+	ctx, cancel := context.WithCancel(context.Background())
+	InitLogWithContext(ctx, InfoLevel, Prod, DisableHostIP(), WithOutput(&out),
+		WithAsync(16), WithBuffer(64*1024, time.Hour))
+
+	Info().Msg("buffered")
+	cancel()
+
+	if !waitFor(t, func() bool { return bytes.Contains(out.Bytes(), []byte(`"message":"buffered"`)) }) {
+		t.Fatalf("event not written out after cancel: %s", out.Bytes())
+	}
+	select {
+	case <-std.lifecycle.async.done:
+	case <-time.After(5 * time.Second):
+		t.Error("the WithAsync goroutine kept running after cancel")
+	}
+}